@@ -0,0 +1,181 @@
+// Copyright 2019 Caleb Spare
+//
+// The contents of this file may be used under the terms of the Apache License,
+// Version 2.0.
+//
+//    (See accompanying file LICENSE or copy at
+//     http://www.apache.org/licenses/LICENSE-2.0)
+//
+// Unless required by applicable law or agreed to in writing, this software
+// is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.
+//
+// appendBinary and appendHex are adapted from the Go standard library's
+// strconv package, which is
+//
+//    Copyright 2009 The Go Authors. All rights reserved.
+//    Use of this source code is governed by a BSD-style
+//    license that can be found at https://golang.org/LICENSE.
+
+package ryu
+
+import (
+	"math"
+	"strconv"
+)
+
+// FormatFloat is a drop-in replacement for strconv.FormatFloat. It converts
+// the floating point number f to a string according to the format fmt and
+// precision prec, assuming that f was obtained from a floating point value of
+// bitSize bits (32 for float32, 64 for float64). The output is identical to
+// that of strconv.FormatFloat for all arguments.
+//
+// The shortest representation (precision -1) is computed with Ryu as by
+// AppendFloat64 and AppendFloat32, and other precisions are computed as by
+// AppendFloat64Prec, which never falls back to arbitrary-precision
+// arithmetic. The 'b', 'x', and 'X' formats work as in strconv.
+func FormatFloat(f float64, fmt byte, prec, bitSize int) string {
+	n := 24
+	if prec > 0 {
+		n += prec
+	}
+	return string(AppendFloat(make([]byte, 0, n), f, fmt, prec, bitSize))
+}
+
+// AppendFloat appends the string form of the floating point number f, as
+// generated by FormatFloat, to b and returns the extended buffer. It is a
+// drop-in replacement for strconv.AppendFloat.
+func AppendFloat(b []byte, f float64, fmt byte, prec, bitSize int) []byte {
+	var (
+		u                 uint64
+		mantBits, expBits uint
+		bias              int
+	)
+	switch bitSize {
+	case 32:
+		u = uint64(math.Float32bits(float32(f)))
+		mantBits, expBits, bias = mantBits32, expBits32, bias32
+	case 64:
+		u = math.Float64bits(f)
+		mantBits, expBits, bias = mantBits64, expBits64, bias64
+	default:
+		panic("ryu: illegal AppendFloat bitSize")
+	}
+	neg := u>>(mantBits+expBits) != 0
+	mant := u & (uint64(1)<<mantBits - 1)
+	exp := int(u>>mantBits) & (1<<expBits - 1)
+	if exp == 1<<expBits-1 {
+		return appendSpecial(b, neg, false, mant == 0)
+	}
+
+	switch fmt {
+	case 'b', 'x', 'X':
+		if exp == 0 {
+			exp++
+		} else {
+			mant |= uint64(1) << mantBits
+		}
+		exp -= bias
+		if fmt == 'b' {
+			return appendBinary(b, neg, mant, exp-int(mantBits))
+		}
+		return appendHex(b, prec, fmt, neg, mant, exp, mantBits)
+	}
+
+	if bitSize == 32 {
+		if prec < 0 {
+			return appendFloat32Shortest(b, float32(f), fmt)
+		}
+		// A float32 is exactly representable as a float64,
+		// so the digits are the same for any fixed precision.
+		f = float64(float32(f))
+	}
+	return AppendFloat64Prec(b, f, fmt, prec)
+}
+
+// appendBinary appends mant * 2^exp in the form -ddddp±ddd.
+func appendBinary(b []byte, neg bool, mant uint64, exp int) []byte {
+	if neg {
+		b = append(b, '-')
+	}
+	b = strconv.AppendUint(b, mant, 10)
+	b = append(b, 'p')
+	if exp >= 0 {
+		b = append(b, '+')
+	}
+	return strconv.AppendInt(b, int64(exp), 10)
+}
+
+// appendHex appends mant * 2^(exp-mantBits) in the form -0x1.yyyyyyyyp±dd
+// (or -0x0p+00 for zero), rounding to prec hexadecimal digits after the
+// point unless prec is negative.
+func appendHex(b []byte, prec int, fmt byte, neg bool, mant uint64, exp int, mantBits uint) []byte {
+	if mant == 0 {
+		exp = 0
+	}
+
+	// Shift the digits so that the leading 1 (if any) is at bit 1<<60.
+	mant <<= 60 - mantBits
+	for mant != 0 && mant&(1<<60) == 0 {
+		mant <<= 1
+		exp--
+	}
+
+	// Round to prec digits, to even.
+	if prec >= 0 && prec < 15 {
+		shift := uint(prec * 4)
+		extra := (mant << shift) & (1<<60 - 1)
+		mant >>= 60 - shift
+		if extra|(mant&1) > 1<<59 {
+			mant++
+		}
+		mant <<= 60 - shift
+		if mant&(1<<61) != 0 {
+			// Wrapped around.
+			mant >>= 1
+			exp++
+		}
+	}
+
+	hex := "0123456789abcdef"
+	p := byte('p')
+	if fmt == 'X' {
+		hex = "0123456789ABCDEF"
+		p = 'P'
+	}
+
+	// The sign, 0x, and the leading digit.
+	if neg {
+		b = append(b, '-')
+	}
+	b = append(b, '0', fmt, '0'+byte((mant>>60)&1))
+
+	// The fraction.
+	mant <<= 4 // remove the leading digit
+	if prec < 0 && mant != 0 {
+		b = append(b, '.')
+		for mant != 0 {
+			b = append(b, hex[(mant>>60)&15])
+			mant <<= 4
+		}
+	} else if prec > 0 {
+		b = append(b, '.')
+		for i := 0; i < prec; i++ {
+			b = append(b, hex[(mant>>60)&15])
+			mant <<= 4
+		}
+	}
+
+	// The exponent, with at least two digits.
+	b = append(b, p)
+	if exp < 0 {
+		b = append(b, '-')
+		exp = -exp
+	} else {
+		b = append(b, '+')
+	}
+	if exp < 10 {
+		b = append(b, '0')
+	}
+	return strconv.AppendInt(b, int64(exp), 10)
+}
@@ -0,0 +1,96 @@
+// Copyright 2019 Caleb Spare
+//
+// The contents of this file may be used under the terms of the Apache License,
+// Version 2.0.
+//
+//    (See accompanying file LICENSE or copy at
+//     http://www.apache.org/licenses/LICENSE-2.0)
+//
+// Unless required by applicable law or agreed to in writing, this software
+// is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.
+
+package ryu
+
+import (
+	"math"
+	"math/rand"
+	"strconv"
+	"testing"
+)
+
+var appendFloatFormats = []byte{'b', 'e', 'E', 'f', 'g', 'G', 'x', 'X', 'q'}
+
+func TestFormatFloat(t *testing.T) {
+	cases := append(genericTestCases, float64TestCases...)
+	cases = append(cases, layoutTestCases...)
+	cases = append(cases, 0.5, 1.25, 2.5e-7, 0.0009765625, 1<<60, 1e-320)
+	for _, bitSize := range []int{32, 64} {
+		for _, fmt := range appendFloatFormats {
+			for _, prec := range []int{-1, 0, 1, 2, 3, 5, 8, 9, 13, 14, 15, 16, 17, 18, 20, 30, 60, 400} {
+				for _, f := range cases {
+					testFormatFloat(t, f, fmt, prec, bitSize)
+				}
+			}
+		}
+	}
+}
+
+func TestFormatFloatRandomPrec(t *testing.T) {
+	// strconv's shortest output had bugs before Go 1.17
+	// (https://golang.org/issue/29491), so only check other precisions
+	// here. The shortest output is checked against the verify package
+	// elsewhere.
+	for i := 0; i < 20000; i++ {
+		f := math.Float64frombits(rand.Uint64())
+		fmt := appendFloatFormats[rand.Intn(len(appendFloatFormats))]
+		prec := rand.Intn(25)
+		if fmt == 'b' || fmt == 'x' || fmt == 'X' {
+			prec = rand.Intn(20) - 1
+		}
+		testFormatFloat(t, f, fmt, prec, 64)
+		testFormatFloat(t, f, fmt, prec, 32)
+	}
+}
+
+func testFormatFloat(t *testing.T, f float64, fmt byte, prec, bitSize int) {
+	t.Helper()
+	got := FormatFloat(f, fmt, prec, bitSize)
+	want := strconv.FormatFloat(f, fmt, prec, bitSize)
+	if got != want {
+		t.Errorf("FormatFloat(%g, %q, %d, %d): got %q; want %q", f, fmt, prec, bitSize, got, want)
+	}
+}
+
+func TestAppendFloatBitSize(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Error("AppendFloat with bitSize 16 did not panic")
+		}
+	}()
+	AppendFloat(nil, 1, 'e', -1, 16)
+}
+
+func BenchmarkAppendFloat(b *testing.B) {
+	for _, bb := range []struct {
+		name    string
+		f       float64
+		fmt     byte
+		prec    int
+		bitSize int
+	}{
+		{"64Shortest", 123.45, 'g', -1, 64},
+		{"32Shortest", 123.45, 'g', -1, 32},
+		{"64Fixed", 123.45, 'f', 6, 64},
+		{"64Exp", 1.2345e-300, 'e', 16, 64},
+		{"64Hex", 123.45, 'x', -1, 64},
+	} {
+		b.Run(bb.name, func(b *testing.B) {
+			var buf []byte
+			for i := 0; i < b.N; i++ {
+				buf = AppendFloat(buf[:0], bb.f, bb.fmt, bb.prec, bb.bitSize)
+			}
+			sinkb = buf
+		})
+	}
+}
@@ -0,0 +1,277 @@
+// Copyright 2019 Caleb Spare
+//
+// The contents of this file may be used under the terms of the Apache License,
+// Version 2.0.
+//
+//    (See accompanying file LICENSE or copy at
+//     http://www.apache.org/licenses/LICENSE-2.0)
+//
+// Unless required by applicable law or agreed to in writing, this software
+// is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.
+
+package main
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"math/big"
+	"math/bits"
+	"strconv"
+
+	"github.com/cespare/ryu"
+)
+
+const (
+	mantBits64 = 52
+	expBits64  = 11
+	bias64     = 1023
+
+	// These match the float64 tables of package ryu (see maketables.go).
+	pow5NumBits64    = 121
+	pow5InvNumBits64 = 122
+)
+
+// explain writes the intermediate values that the float64ToDecimal function
+// of package ryu computes for f to w.
+//
+// That function is not exported, so explain repeats its steps: it computes
+// the same table entries from their definitions and does the same 128-bit
+// multiplications and digit removal loops. The result is checked against
+// ryu.Decimal64.
+func explain(w io.Writer, f float64) error {
+	show := func(name string, v interface{}) {
+		fmt.Fprintf(w, "  %-18s %v\n", name, v)
+	}
+
+	u := math.Float64bits(f)
+	mant := u & (1<<mantBits64 - 1)
+	exp := (u >> mantBits64) & (1<<expBits64 - 1)
+	show("bits", fmt.Sprintf("%#016x", u))
+	show("sign", u>>63)
+	show("biased exponent", exp)
+	show("mantissa", fmt.Sprintf("%#013x", mant))
+	if exp == 1<<expBits64-1 || (exp == 0 && mant == 0) {
+		show("special value", "ryu does not compute anything")
+		return nil
+	}
+	if e := int(exp) - bias64; e <= mantBits64 && e >= 0 {
+		m := mant | 1<<mantBits64
+		if shift := uint(mantBits64 - e); m>>shift<<shift == m {
+			show("exact integer", "ryu takes a fast path; the steps below give the same digits")
+		}
+	}
+
+	// Step 1: Decode the floating-point number.
+	var e2 int32
+	var m2 uint64
+	if exp == 0 {
+		e2 = 1 - bias64 - mantBits64 - 2
+		m2 = mant
+	} else {
+		e2 = int32(exp) - bias64 - mantBits64 - 2
+		m2 = uint64(1)<<mantBits64 | mant
+	}
+	acceptBounds := m2&1 == 0
+	show("e2", e2)
+	show("m2", m2)
+
+	// Step 2: Determine the interval of valid decimal representations.
+	mv := 4 * m2
+	mmShift := boolToUint64(mant != 0 || exp <= 1)
+	show("mv", mv)
+	show("mp", mv+2)
+	show("mm", mv-1-mmShift)
+	show("acceptBounds", acceptBounds)
+
+	// Step 3: Convert to a decimal power base using 128-bit arithmetic.
+	var (
+		q                 uint32
+		e10               int32
+		vr, vp, vm        uint64
+		vmIsTrailingZeros bool
+		vrIsTrailingZeros bool
+	)
+	if e2 >= 0 {
+		q = log10Pow2(e2) - boolToUint32(e2 > 3)
+		e10 = int32(q)
+		k := pow5InvNumBits64 + pow5Bits(int32(q)) - 1
+		i := -e2 + int32(q) + k
+		mul := pow5InvMul64(q)
+		vr = mulShift64(4*m2, mul, i)
+		vp = mulShift64(4*m2+2, mul, i)
+		vm = mulShift64(4*m2-1-mmShift, mul, i)
+		if q <= 21 {
+			if mv%5 == 0 {
+				vrIsTrailingZeros = multipleOfPowerOfFive64(mv, q)
+			} else if acceptBounds {
+				vmIsTrailingZeros = multipleOfPowerOfFive64(mv-1-mmShift, q)
+			} else if multipleOfPowerOfFive64(mv+2, q) {
+				vp--
+			}
+		}
+	} else {
+		q = log10Pow5(-e2) - boolToUint32(-e2 > 1)
+		e10 = int32(q) + e2
+		i := -e2 - int32(q)
+		k := pow5Bits(i) - pow5NumBits64
+		j := int32(q) - k
+		mul := pow5Mul64(uint32(i))
+		vr = mulShift64(4*m2, mul, j)
+		vp = mulShift64(4*m2+2, mul, j)
+		vm = mulShift64(4*m2-1-mmShift, mul, j)
+		if q <= 1 {
+			vrIsTrailingZeros = true
+			if acceptBounds {
+				vmIsTrailingZeros = mmShift == 1
+			} else {
+				vp--
+			}
+		} else if q < 63 {
+			vrIsTrailingZeros = uint32(bits.TrailingZeros64(mv)) >= q-1
+		}
+	}
+	show("q", q)
+	show("e10", e10)
+	show("vr", vr)
+	show("vp", vp)
+	show("vm", vm)
+	show("vrIsTrailingZeros", vrIsTrailingZeros)
+	show("vmIsTrailingZeros", vmIsTrailingZeros)
+
+	// Step 4: Find the shortest decimal representation
+	// in the interval of valid representations.
+	vrDigits := strconv.FormatUint(vr, 10)
+	var removed int32
+	var lastRemovedDigit uint8
+	var out uint64
+	if vmIsTrailingZeros || vrIsTrailingZeros {
+		show("loop", "general case")
+		for vp/10 > vm/10 {
+			vmIsTrailingZeros = vmIsTrailingZeros && vm%10 == 0
+			vrIsTrailingZeros = vrIsTrailingZeros && lastRemovedDigit == 0
+			lastRemovedDigit = uint8(vr % 10)
+			vr, vp, vm = vr/10, vp/10, vm/10
+			removed++
+		}
+		if vmIsTrailingZeros {
+			for vm%10 == 0 {
+				vrIsTrailingZeros = vrIsTrailingZeros && lastRemovedDigit == 0
+				lastRemovedDigit = uint8(vr % 10)
+				vr, vp, vm = vr/10, vp/10, vm/10
+				removed++
+			}
+		}
+		if vrIsTrailingZeros && lastRemovedDigit == 5 && vr%2 == 0 {
+			// Round even if the exact number is .....50..0.
+			lastRemovedDigit = 4
+		}
+		show("lastRemovedDigit", lastRemovedDigit)
+		out = vr
+		if (vr == vm && (!acceptBounds || !vmIsTrailingZeros)) || lastRemovedDigit >= 5 {
+			out++
+		}
+	} else {
+		show("loop", "common case")
+		roundUp := false
+		for vp/100 > vm/100 {
+			// As in ryu, remove two digits at a time first.
+			roundUp = vr%100 >= 50
+			vr, vp, vm = vr/100, vp/100, vm/100
+			removed += 2
+		}
+		for vp/10 > vm/10 {
+			roundUp = vr%10 >= 5
+			vr, vp, vm = vr/10, vp/10, vm/10
+			removed++
+		}
+		show("roundUp", roundUp)
+		out = vr + boolToUint64(vr == vm || roundUp)
+	}
+	show("removed", fmt.Sprintf("%d digits (%s)", removed, vrDigits[len(vrDigits)-int(removed):]))
+	show("output", fmt.Sprintf("%de%d", out, e10+removed))
+
+	digits, e, _, _ := ryu.Decimal64(f)
+	if digits != out || e != e10+removed {
+		return fmt.Errorf("explain computed %de%d for %v, but ryu.Decimal64 gives %de%d",
+			out, e10+removed, f, digits, e)
+	}
+	return nil
+}
+
+type uint128 struct {
+	lo uint64
+	hi uint64
+}
+
+// pow5Mul64 returns the entry of ryu's pow5Split64 table for i:
+// 5^i with pow5NumBits64 bits (rounded down).
+func pow5Mul64(i uint32) uint128 {
+	v := pow5(i)
+	return toUint128(shift(v, pow5NumBits64-v.BitLen()))
+}
+
+// pow5InvMul64 returns the entry of ryu's pow5InvSplit64 table for i:
+// 2^(floor(log_2(5^i))+pow5InvNumBits64) / 5^i, rounded down, plus 1.
+func pow5InvMul64(i uint32) uint128 {
+	v := pow5(i)
+	inv := shift(big.NewInt(1), v.BitLen()-1+pow5InvNumBits64)
+	inv.Quo(inv, v)
+	return toUint128(inv.Add(inv, big.NewInt(1)))
+}
+
+func pow5(i uint32) *big.Int {
+	return new(big.Int).Exp(big.NewInt(5), big.NewInt(int64(i)), nil)
+}
+
+// shift shifts v left by n bits, or right by -n bits if n is negative.
+func shift(v *big.Int, n int) *big.Int {
+	if n < 0 {
+		return v.Rsh(v, uint(-n))
+	}
+	return v.Lsh(v, uint(n))
+}
+
+func toUint128(v *big.Int) uint128 {
+	lo := new(big.Int).And(v, new(big.Int).SetUint64(math.MaxUint64))
+	return uint128{lo: lo.Uint64(), hi: new(big.Int).Rsh(v, 64).Uint64()}
+}
+
+// mulShift64 is ryu's mulShift64: it returns (m * mul) >> shift, where
+// 64 <= shift < 128.
+func mulShift64(m uint64, mul uint128, shift int32) uint64 {
+	hihi, hilo := bits.Mul64(m, mul.hi)
+	lohi, _ := bits.Mul64(m, mul.lo)
+	lo, carry := bits.Add64(lohi, hilo, 0)
+	hi := hihi + carry
+	s := uint(shift - 64)
+	return hi<<(64-s) | lo>>s
+}
+
+func log10Pow2(e int32) uint32 { return (uint32(e) * 78913) >> 18 }
+func log10Pow5(e int32) uint32 { return (uint32(e) * 732923) >> 20 }
+func pow5Bits(e int32) int32   { return int32((uint32(e)*1217359)>>19 + 1) }
+
+func multipleOfPowerOfFive64(v uint64, p uint32) bool {
+	var n uint32
+	for v%5 == 0 {
+		v /= 5
+		n++
+	}
+	return n >= p
+}
+
+func boolToUint32(b bool) uint32 {
+	if b {
+		return 1
+	}
+	return 0
+}
+
+func boolToUint64(b bool) uint64 {
+	if b {
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,56 @@
+// Copyright 2019 Caleb Spare
+//
+// The contents of this file may be used under the terms of the Apache License,
+// Version 2.0.
+//
+//    (See accompanying file LICENSE or copy at
+//     http://www.apache.org/licenses/LICENSE-2.0)
+//
+// Unless required by applicable law or agreed to in writing, this software
+// is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.
+
+package main
+
+import (
+	"io/ioutil"
+	"math"
+	"math/rand"
+	"testing"
+)
+
+func TestExplain(t *testing.T) {
+	// explain checks its result against ryu.Decimal64.
+	for _, f := range []float64{
+		0, math.Inf(1), math.NaN(), 1, 0.3, 1e23, 5e-324, math.MaxFloat64,
+		2.2250738585072014e-308, 9007199254740993, 123456789,
+	} {
+		if err := explain(ioutil.Discard, f); err != nil {
+			t.Error(err)
+		}
+	}
+	for i := 0; i < 10000; i++ {
+		f := math.Float64frombits(rand.Uint64())
+		if err := explain(ioutil.Discard, f); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestIsHexBits(t *testing.T) {
+	for _, tt := range []struct {
+		s    string
+		want bool
+	}{
+		{"0x3ff0000000000000", true},
+		{"0X7F800000", true},
+		{"0x1p-2", false},
+		{"0x1.8p1", false},
+		{"0x", false},
+		{"1.5", false},
+	} {
+		if got := isHexBits(tt.s); got != tt.want {
+			t.Errorf("isHexBits(%q): got %t; want %t", tt.s, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,255 @@
+// Copyright 2019 Caleb Spare
+//
+// The contents of this file may be used under the terms of the Apache License,
+// Version 2.0.
+//
+//    (See accompanying file LICENSE or copy at
+//     http://www.apache.org/licenses/LICENSE-2.0)
+//
+// Unless required by applicable law or agreed to in writing, this software
+// is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.
+
+// Command ryu formats floating-point numbers using package ryu.
+//
+// Usage:
+//
+//	ryu [flags] [value ...]
+//
+// Each value is either a floating-point literal such as 0.1, 1e-7, or
+// 0x1p-3, parsed by strconv.ParseFloat, or the bits of a float as a
+// hexadecimal integer such as 0x3ff0000000000000. If no values are given,
+// ryu reads whitespace-separated values from standard input. With -binary,
+// it instead reads raw IEEE 754 values in little-endian byte order from
+// standard input.
+//
+// The flags are:
+//
+//	-fmt e|f|g|json
+//		output format: FormatFloat64, FormatFloat64Fixed,
+//		FormatFloat64General, or AppendJSONFloat64 (default e)
+//	-32
+//		treat the values as float32s
+//	-binary
+//		read raw binary values from standard input
+//	-explain
+//		print the intermediate values of the Ryu algorithm
+//		(float64 only)
+package main
+
+import (
+	"bufio"
+	"encoding/binary"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"math"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/cespare/ryu"
+)
+
+func main() {
+	log.SetFlags(0)
+	log.SetPrefix("ryu: ")
+	var (
+		format   = flag.String("fmt", "e", "output format: e, f, g, or json")
+		is32     = flag.Bool("32", false, "treat the values as float32s")
+		isBinary = flag.Bool("binary", false, "read raw little-endian binary values from stdin")
+		doExpl   = flag.Bool("explain", false, "print the intermediate values of the Ryu algorithm")
+	)
+	flag.Usage = func() {
+		fmt.Fprintf(os.Stderr, "usage: ryu [flags] [value ...]\n")
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+	switch *format {
+	case "e", "f", "g", "json":
+	default:
+		log.Printf("unknown format %q", *format)
+		flag.Usage()
+		os.Exit(2)
+	}
+	if *doExpl && *is32 {
+		log.Print("-explain is only supported for float64s")
+		os.Exit(2)
+	}
+	if *isBinary && flag.NArg() > 0 {
+		log.Print("-binary reads from stdin and takes no arguments")
+		os.Exit(2)
+	}
+
+	c := converter{
+		w:       bufio.NewWriter(os.Stdout),
+		format:  *format,
+		is32:    *is32,
+		explain: *doExpl,
+	}
+	switch {
+	case *isBinary:
+		c.readBinary(bufio.NewReader(os.Stdin))
+	case flag.NArg() > 0:
+		for _, arg := range flag.Args() {
+			c.convertString(arg)
+		}
+	default:
+		c.readText(os.Stdin)
+	}
+	if err := c.w.Flush(); err != nil {
+		log.Fatal(err)
+	}
+	if c.failed {
+		os.Exit(1)
+	}
+}
+
+type converter struct {
+	w       *bufio.Writer
+	format  string
+	is32    bool
+	explain bool
+	failed  bool
+	buf     []byte
+}
+
+func (c *converter) errorf(format string, args ...interface{}) {
+	c.w.Flush()
+	log.Printf(format, args...)
+	c.failed = true
+}
+
+func (c *converter) readText(r io.Reader) {
+	scanner := bufio.NewScanner(r)
+	scanner.Split(bufio.ScanWords)
+	for scanner.Scan() {
+		c.convertString(scanner.Text())
+	}
+	if err := scanner.Err(); err != nil {
+		c.errorf("error reading input: %s", err)
+	}
+}
+
+func (c *converter) readBinary(r io.Reader) {
+	size := 8
+	if c.is32 {
+		size = 4
+	}
+	b := make([]byte, size)
+	for {
+		if _, err := io.ReadFull(r, b); err != nil {
+			switch err {
+			case io.EOF:
+			case io.ErrUnexpectedEOF:
+				c.errorf("input ends with a partial %d-byte value", size)
+			default:
+				c.errorf("error reading input: %s", err)
+			}
+			return
+		}
+		if c.is32 {
+			c.convert32(math.Float32frombits(binary.LittleEndian.Uint32(b)))
+		} else {
+			c.convert64(math.Float64frombits(binary.LittleEndian.Uint64(b)))
+		}
+	}
+}
+
+// convertString parses s as described in the package comment and converts
+// the result.
+func (c *converter) convertString(s string) {
+	bitSize := 64
+	if c.is32 {
+		bitSize = 32
+	}
+	var f float64
+	if isHexBits(s) {
+		u, err := strconv.ParseUint(s[2:], 16, bitSize)
+		if err != nil {
+			c.errorf("bad float%d bits %q: %s", bitSize, s, numErr(err))
+			return
+		}
+		if c.is32 {
+			c.convert32(math.Float32frombits(uint32(u)))
+			return
+		}
+		f = math.Float64frombits(u)
+	} else {
+		var err error
+		f, err = strconv.ParseFloat(s, bitSize)
+		if err != nil && numErr(err) != strconv.ErrRange {
+			c.errorf("bad float%d %q: %s", bitSize, s, numErr(err))
+			return
+		}
+	}
+	if c.is32 {
+		c.convert32(float32(f))
+	} else {
+		c.convert64(f)
+	}
+}
+
+// isHexBits reports whether s is a hexadecimal integer (rather than a
+// hexadecimal floating-point literal, which has a 'p' exponent).
+func isHexBits(s string) bool {
+	return len(s) > 2 && s[0] == '0' && (s[1] == 'x' || s[1] == 'X') &&
+		!strings.ContainsAny(s, "pP.")
+}
+
+// numErr returns the Err field of err if it is a *strconv.NumError,
+// such as strconv.ErrSyntax or strconv.ErrRange, and err otherwise.
+func numErr(err error) error {
+	if e, ok := err.(*strconv.NumError); ok {
+		return e.Err
+	}
+	return err
+}
+
+func (c *converter) convert64(f float64) {
+	b := c.buf[:0]
+	var err error
+	switch c.format {
+	case "e":
+		b = ryu.AppendFloat64(b, f)
+	case "f":
+		b = ryu.AppendFloat64Fixed(b, f)
+	case "g":
+		b = ryu.AppendFloat64General(b, f)
+	case "json":
+		b, err = ryu.AppendJSONFloat64(b, f)
+	}
+	c.output(b, err)
+	if c.explain {
+		if err := explain(c.w, f); err != nil {
+			c.errorf("%s", err)
+		}
+	}
+}
+
+func (c *converter) convert32(f float32) {
+	b := c.buf[:0]
+	var err error
+	switch c.format {
+	case "e":
+		b = ryu.AppendFloat32(b, f)
+	case "f":
+		b = ryu.AppendFloat32Fixed(b, f)
+	case "g":
+		b = ryu.AppendFloat32General(b, f)
+	case "json":
+		b, err = ryu.AppendJSONFloat32(b, f)
+	}
+	c.output(b, err)
+}
+
+func (c *converter) output(b []byte, err error) {
+	c.buf = b
+	if err != nil {
+		c.errorf("%s", err)
+		return
+	}
+	c.w.Write(b)
+	c.w.WriteByte('\n')
+}
@@ -0,0 +1,84 @@
+// Copyright 2019 Caleb Spare
+//
+// The contents of this file may be used under the terms of the Apache License,
+// Version 2.0.
+//
+//    (See accompanying file LICENSE or copy at
+//     http://www.apache.org/licenses/LICENSE-2.0)
+//
+// Unless required by applicable law or agreed to in writing, this software
+// is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.
+
+package ryu
+
+import (
+	"reflect"
+	"unsafe"
+)
+
+// FormatComplex128 converts the complex number c to a string of the form
+// (a+bi), where a and b are the real and imaginary parts formatted using the
+// shortest representation in the format fmt, which is one of 'e', 'E', 'f',
+// 'g', or 'G'. It behaves like strconv.FormatComplex(c, fmt, -1, 128).
+//
+// Any other format is printed as '%' followed by the format character in
+// place of each part.
+func FormatComplex128(c complex128, fmt byte) string {
+	b := make([]byte, 0, 2*24+3)
+	b = AppendComplex128(b, c, fmt)
+
+	// Convert the output to a string without copying.
+	var s string
+	sh := (*reflect.StringHeader)(unsafe.Pointer(&s))
+	sh.Data = uintptr(unsafe.Pointer(&b[0]))
+	sh.Len = len(b)
+	return s
+}
+
+// AppendComplex128 appends the string form of the complex number c, as
+// generated by FormatComplex128, to b and returns the extended buffer.
+func AppendComplex128(b []byte, c complex128, fmt byte) []byte {
+	b = append(b, '(')
+	b = appendFloat64Shortest(b, real(c), fmt)
+	n := len(b)
+	b = appendFloat64Shortest(b, imag(c), fmt)
+	return appendImagSuffix(b, n)
+}
+
+// FormatComplex64 is like FormatComplex128 for complex64s, whose parts are
+// formatted as float32s. It behaves like strconv.FormatComplex(complex128(c),
+// fmt, -1, 64).
+func FormatComplex64(c complex64, fmt byte) string {
+	b := make([]byte, 0, 2*15+3)
+	b = AppendComplex64(b, c, fmt)
+
+	// Convert the output to a string without copying.
+	var s string
+	sh := (*reflect.StringHeader)(unsafe.Pointer(&s))
+	sh.Data = uintptr(unsafe.Pointer(&b[0]))
+	sh.Len = len(b)
+	return s
+}
+
+// AppendComplex64 appends the string form of the complex number c, as
+// generated by FormatComplex64, to b and returns the extended buffer.
+func AppendComplex64(b []byte, c complex64, fmt byte) []byte {
+	b = append(b, '(')
+	b = appendFloat32Shortest(b, real(c), fmt)
+	n := len(b)
+	b = appendFloat32Shortest(b, imag(c), fmt)
+	return appendImagSuffix(b, n)
+}
+
+// appendImagSuffix finishes a complex number whose imaginary part was
+// written to b[n:]. Like strconv, it makes sure that the imaginary part has
+// a sign (which it lacks if it is positive or NaN).
+func appendImagSuffix(b []byte, n int) []byte {
+	if b[n] != '+' && b[n] != '-' {
+		b = append(b, 0)
+		copy(b[n+1:], b[n:])
+		b[n] = '+'
+	}
+	return append(b, 'i', ')')
+}
@@ -0,0 +1,98 @@
+// Copyright 2019 Caleb Spare
+//
+// The contents of this file may be used under the terms of the Apache License,
+// Version 2.0.
+//
+//    (See accompanying file LICENSE or copy at
+//     http://www.apache.org/licenses/LICENSE-2.0)
+//
+// Unless required by applicable law or agreed to in writing, this software
+// is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.
+
+package ryu
+
+import (
+	"math"
+	"strconv"
+)
+
+// Kind is the class of a floating-point number.
+type Kind uint8
+
+const (
+	KindFinite Kind = iota // a finite, nonzero number
+	KindZero               // positive or negative zero
+	KindInf                // positive or negative infinity
+	KindNaN                // not a number
+)
+
+var kindNames = [...]string{
+	KindFinite: "KindFinite",
+	KindZero:   "KindZero",
+	KindInf:    "KindInf",
+	KindNaN:    "KindNaN",
+}
+
+func (k Kind) String() string {
+	if int(k) < len(kindNames) {
+		return kindNames[k]
+	}
+	return "Kind(" + strconv.Itoa(int(k)) + ")"
+}
+
+// Decimal32 returns the decimal decomposition of the 32-bit floating point
+// number f that is printed by FormatFloat32: if kind is KindFinite, the
+// absolute value of f is closest to digits * 10^exp among all numbers with as
+// few digits that parse back to f. The sign of f (including the sign of zeros,
+// infinities, and NaNs) is given by neg. Unless kind is KindFinite, digits and
+// exp are zero.
+func Decimal32(f float32) (digits uint32, exp int32, neg bool, kind Kind) {
+	u := math.Float32bits(f)
+	neg = u>>(mantBits32+expBits32) != 0
+	mant := u & (uint32(1)<<mantBits32 - 1)
+	e := (u >> mantBits32) & (uint32(1)<<expBits32 - 1)
+
+	switch {
+	case e == uint32(1)<<expBits32-1 && mant != 0:
+		return 0, 0, neg, KindNaN
+	case e == uint32(1)<<expBits32-1:
+		return 0, 0, neg, KindInf
+	case e == 0 && mant == 0:
+		return 0, 0, neg, KindZero
+	}
+
+	d, ok := float32ToDecimalExactInt(mant, e)
+	if !ok {
+		d = float32ToDecimal(mant, e)
+	}
+	return d.m, d.e, neg, KindFinite
+}
+
+// Decimal64 returns the decimal decomposition of the 64-bit floating point
+// number f that is printed by FormatFloat64: if kind is KindFinite, the
+// absolute value of f is closest to digits * 10^exp among all numbers with as
+// few digits that parse back to f. The sign of f (including the sign of zeros,
+// infinities, and NaNs) is given by neg. Unless kind is KindFinite, digits and
+// exp are zero.
+func Decimal64(f float64) (digits uint64, exp int32, neg bool, kind Kind) {
+	u := math.Float64bits(f)
+	neg = u>>(mantBits64+expBits64) != 0
+	mant := u & (uint64(1)<<mantBits64 - 1)
+	e := (u >> mantBits64) & (uint64(1)<<expBits64 - 1)
+
+	switch {
+	case e == uint64(1)<<expBits64-1 && mant != 0:
+		return 0, 0, neg, KindNaN
+	case e == uint64(1)<<expBits64-1:
+		return 0, 0, neg, KindInf
+	case e == 0 && mant == 0:
+		return 0, 0, neg, KindZero
+	}
+
+	d, ok := float64ToDecimalExactInt(mant, e)
+	if !ok {
+		d = float64ToDecimal(mant, e)
+	}
+	return d.m, d.e, neg, KindFinite
+}
@@ -0,0 +1,114 @@
+// Copyright 2019 Caleb Spare
+//
+// The contents of this file may be used under the terms of the Apache License,
+// Version 2.0.
+//
+//    (See accompanying file LICENSE or copy at
+//     http://www.apache.org/licenses/LICENSE-2.0)
+//
+// Unless required by applicable law or agreed to in writing, this software
+// is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.
+
+package ryu
+
+import "io"
+
+const encoderBufSize = 4096
+
+// An Encoder writes floating point numbers to an io.Writer, separated by a
+// separator string (a newline by default). It buffers its output; call Flush
+// after the last value to make sure all of it has been written.
+//
+// As with bufio.Writer, if an error occurs writing to the underlying
+// io.Writer, no more data is accepted and all subsequent calls to the
+// Write methods and Flush return the error.
+type Encoder struct {
+	w     io.Writer
+	buf   []byte
+	sep   string
+	wrote bool // whether a value has been written (and needs a separator)
+	err   error
+}
+
+// NewEncoder returns a new Encoder that writes to w.
+func NewEncoder(w io.Writer) *Encoder {
+	return &Encoder{
+		w:   w,
+		buf: make([]byte, 0, encoderBufSize),
+		sep: "\n",
+	}
+}
+
+// SetSeparator sets the string that is written between consecutive values.
+// It may be empty.
+func (e *Encoder) SetSeparator(sep string) {
+	e.sep = sep
+}
+
+// WriteFloat64 writes f in the form generated by FormatFloat64.
+func (e *Encoder) WriteFloat64(f float64) error {
+	if err := e.reserve(24); err != nil {
+		return err
+	}
+	e.buf = AppendFloat64(e.buf, f)
+	return nil
+}
+
+// WriteFloat32 writes f in the form generated by FormatFloat32.
+func (e *Encoder) WriteFloat32(f float32) error {
+	if err := e.reserve(15); err != nil {
+		return err
+	}
+	e.buf = AppendFloat32(e.buf, f)
+	return nil
+}
+
+// WriteFloat64Fixed writes f in the form generated by FormatFloat64Fixed.
+func (e *Encoder) WriteFloat64Fixed(f float64) error {
+	// No shortest representation has a digit below 10^-324, so the longest
+	// output is for -5e-324: "-0." followed by 323 zeros and "5".
+	if err := e.reserve(327); err != nil {
+		return err
+	}
+	e.buf = AppendFloat64Fixed(e.buf, f)
+	return nil
+}
+
+// reserve makes room in the buffer for a separator followed by a value of
+// at most n bytes, flushing it if necessary, and then writes the separator.
+func (e *Encoder) reserve(n int) error {
+	if e.err != nil {
+		return e.err
+	}
+	if len(e.buf)+len(e.sep)+n > cap(e.buf) {
+		if err := e.Flush(); err != nil {
+			return err
+		}
+	}
+	if e.wrote {
+		e.buf = append(e.buf, e.sep...)
+	}
+	e.wrote = true
+	return nil
+}
+
+// Flush writes any buffered data to the underlying io.Writer.
+func (e *Encoder) Flush() error {
+	if e.err != nil {
+		return e.err
+	}
+	if len(e.buf) == 0 {
+		return nil
+	}
+	n, err := e.w.Write(e.buf)
+	if n < len(e.buf) && err == nil {
+		err = io.ErrShortWrite
+	}
+	if err != nil {
+		e.err = err
+		return err
+	}
+	e.buf = e.buf[:0]
+	return nil
+}
@@ -0,0 +1,141 @@
+// Copyright 2019 Caleb Spare
+//
+// The contents of this file may be used under the terms of the Apache License,
+// Version 2.0.
+//
+//    (See accompanying file LICENSE or copy at
+//     http://www.apache.org/licenses/LICENSE-2.0)
+//
+// Unless required by applicable law or agreed to in writing, this software
+// is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.
+
+package ryu
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"io/ioutil"
+	"math"
+	"math/rand"
+	"strings"
+	"testing"
+)
+
+func TestEncoder(t *testing.T) {
+	for _, sep := range []string{"\n", ",", "", ", "} {
+		var buf bytes.Buffer
+		enc := NewEncoder(&buf)
+		enc.SetSeparator(sep)
+		var want []string
+		// Write enough values to fill the buffer several times.
+		for i := 0; i < 3000; i++ {
+			f := math.Float64frombits(rand.Uint64())
+			var err error
+			switch i % 3 {
+			case 0:
+				err = enc.WriteFloat64(f)
+				want = append(want, FormatFloat64(f))
+			case 1:
+				err = enc.WriteFloat32(float32(f))
+				want = append(want, FormatFloat32(float32(f)))
+			case 2:
+				err = enc.WriteFloat64Fixed(f)
+				want = append(want, FormatFloat64Fixed(f))
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+		}
+		if err := enc.Flush(); err != nil {
+			t.Fatal(err)
+		}
+		if got := buf.String(); got != strings.Join(want, sep) {
+			t.Errorf("with separator %q: got %q; want %q", sep, got, strings.Join(want, sep))
+		}
+	}
+}
+
+func TestEncoderFixedMaxLen(t *testing.T) {
+	// WriteFloat64Fixed reserves 327 bytes.
+	if n := len(FormatFloat64Fixed(-5e-324)); n != 327 {
+		t.Errorf("len(FormatFloat64Fixed(-5e-324)) = %d; want 327", n)
+	}
+	r := rand.New(rand.NewSource(1))
+	for i := 0; i < 10000; i++ {
+		// Subnormals have the longest outputs.
+		f := -math.Float64frombits(r.Uint64() >> 12)
+		if s := FormatFloat64Fixed(f); len(s) > 327 {
+			t.Fatalf("FormatFloat64Fixed(%g) has length %d > 327", f, len(s))
+		}
+	}
+}
+
+type errorWriter struct {
+	n   int // number of successful writes before failing
+	err error
+}
+
+func (w *errorWriter) Write(b []byte) (int, error) {
+	if w.n == 0 {
+		return 0, w.err
+	}
+	w.n--
+	return len(b), nil
+}
+
+type shortWriter struct{}
+
+func (shortWriter) Write(b []byte) (int, error) {
+	return len(b) / 2, nil
+}
+
+func TestEncoderStickyError(t *testing.T) {
+	errWrite := errors.New("write error")
+	enc := NewEncoder(&errorWriter{n: 1, err: errWrite})
+	var err error
+	for i := 0; i < 1000 && err == nil; i++ {
+		err = enc.WriteFloat64Fixed(math.MaxFloat64)
+	}
+	if err != errWrite {
+		t.Fatalf("got error %v; want %v", err, errWrite)
+	}
+	if err := enc.WriteFloat64(1); err != errWrite {
+		t.Errorf("WriteFloat64 after failure: got error %v; want %v", err, errWrite)
+	}
+	if err := enc.WriteFloat32(1); err != errWrite {
+		t.Errorf("WriteFloat32 after failure: got error %v; want %v", err, errWrite)
+	}
+	if err := enc.Flush(); err != errWrite {
+		t.Errorf("Flush after failure: got error %v; want %v", err, errWrite)
+	}
+
+	enc = NewEncoder(shortWriter{})
+	if err := enc.WriteFloat64(1); err != nil {
+		t.Fatal(err)
+	}
+	if err := enc.Flush(); err != io.ErrShortWrite {
+		t.Errorf("Flush with short write: got error %v; want %v", err, io.ErrShortWrite)
+	}
+}
+
+func TestEncoderAllocs(t *testing.T) {
+	enc := NewEncoder(ioutil.Discard)
+	allocs := testing.AllocsPerRun(1000, func() {
+		enc.WriteFloat64(1.2345e-67)
+		enc.WriteFloat32(1.2345e-7)
+		enc.WriteFloat64Fixed(123.45)
+	})
+	if allocs != 0 {
+		t.Errorf("got %.1f allocations per run; want 0", allocs)
+	}
+}
+
+func BenchmarkEncoder(b *testing.B) {
+	enc := NewEncoder(ioutil.Discard)
+	for i := 0; i < b.N; i++ {
+		enc.WriteFloat64(float64(i) * 1.1)
+	}
+	enc.Flush()
+}
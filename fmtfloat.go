@@ -0,0 +1,231 @@
+// Copyright 2019 Caleb Spare
+//
+// The contents of this file may be used under the terms of the Apache License,
+// Version 2.0.
+//
+//    (See accompanying file LICENSE or copy at
+//     http://www.apache.org/licenses/LICENSE-2.0)
+//
+// Unless required by applicable law or agreed to in writing, this software
+// is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.
+//
+// fmtFloat is adapted from the Go standard library's fmt package, which is
+//
+//    Copyright 2009 The Go Authors. All rights reserved.
+//    Use of this source code is governed by a BSD-style
+//    license that can be found at https://golang.org/LICENSE.
+
+package ryu
+
+import (
+	"fmt"
+	"unicode/utf8"
+)
+
+// F64 is a float64 that formats itself using Ryu. It implements fmt.Formatter
+// and fmt.Stringer, so wrapping a float64 argument in F64 speeds up
+// fmt.Printf and friends without changing their output: every verb, width,
+// precision, and flag ('+', '-', ' ', '0', and '#') is handled exactly as fmt
+// handles a float64.
+type F64 float64
+
+// String returns f in the shortest 'g' format, as fmt prints it with %v.
+func (f F64) String() string {
+	return FormatFloat(float64(f), 'g', -1, 64)
+}
+
+// Format implements fmt.Formatter.
+func (f F64) Format(s fmt.State, verb rune) {
+	formatFloat(s, verb, float64(f), 64, "ryu.F64")
+}
+
+// F32 is a float32 that formats itself using Ryu. It is the float32
+// counterpart of F64.
+type F32 float32
+
+// String returns f in the shortest 'g' format, as fmt prints it with %v.
+func (f F32) String() string {
+	return FormatFloat(float64(f), 'g', -1, 32)
+}
+
+// Format implements fmt.Formatter.
+func (f F32) Format(s fmt.State, verb rune) {
+	formatFloat(s, verb, float64(f), 32, "ryu.F32")
+}
+
+// fmtState holds the flags, width, and precision of a verb.
+type fmtState struct {
+	plus, minus, sharp, space, zero bool
+
+	wid, prec               int
+	widPresent, precPresent bool
+}
+
+func formatFloat(s fmt.State, verb rune, v float64, size int, typ string) {
+	var st fmtState
+	st.plus = s.Flag('+')
+	st.minus = s.Flag('-')
+	st.sharp = s.Flag('#')
+	st.space = s.Flag(' ')
+	st.zero = s.Flag('0')
+	st.wid, st.widPresent = s.Width()
+	st.prec, st.precPresent = s.Precision()
+
+	var buf [32]byte
+	switch verb {
+	case 'v':
+		// fmt treats %+v and %#v as requests for Go or struct-field
+		// syntax, which for floats is the same as %v.
+		st.plus, st.sharp = false, false
+		s.Write(st.fmtFloat(buf[:0], v, size, 'g', -1))
+	case 'b', 'g', 'G', 'x', 'X':
+		s.Write(st.fmtFloat(buf[:0], v, size, byte(verb), -1))
+	case 'f', 'e', 'E':
+		s.Write(st.fmtFloat(buf[:0], v, size, byte(verb), 6))
+	case 'F':
+		s.Write(st.fmtFloat(buf[:0], v, size, 'f', 6))
+	default:
+		// Mirror fmt's %!verb(type=value) output. As in fmt, the value is
+		// printed with %v but keeps the original flags.
+		b := append(buf[:0], "%!"...)
+		if verb < utf8.RuneSelf {
+			b = append(b, byte(verb))
+		} else {
+			var r [utf8.UTFMax]byte
+			b = append(b, r[:utf8.EncodeRune(r[:], verb)]...)
+		}
+		b = append(b, '(')
+		b = append(b, typ...)
+		b = append(b, '=')
+		b = st.fmtFloat(b, v, size, 'g', -1)
+		b = append(b, ')')
+		s.Write(b)
+	}
+}
+
+// fmtFloat appends v formatted like fmt formats a float of the given size
+// with the verb (one of the strconv format bytes) and default precision prec.
+func (st *fmtState) fmtFloat(b []byte, v float64, size int, verb byte, prec int) []byte {
+	// Explicit precision in format specifier overrules default precision.
+	if st.precPresent {
+		prec = st.prec
+	}
+	// Format number, reserving space for leading + sign if needed.
+	start := len(b)
+	num := AppendFloat(append(b, '+'), v, verb, prec, size)
+	if num[start+1] == '-' || num[start+1] == '+' {
+		num = append(num[:start], num[start+1:]...)
+	}
+	// st.space means to add a leading space instead of a "+" sign unless
+	// the sign is explicitly asked for by st.plus.
+	if st.space && num[start] == '+' && !st.plus {
+		num[start] = ' '
+	}
+	// Infinities and NaN don't look like a number so shouldn't be padded
+	// with zeros.
+	if c := num[start+1]; c == 'I' || c == 'N' {
+		// Remove sign before NaN if not asked for.
+		if c == 'N' && !st.space && !st.plus {
+			num = append(num[:start], num[start+1:]...)
+		}
+		return st.pad(b[:start], num[start:], false, true)
+	}
+	// The sharp flag forces printing a decimal point for non-binary formats
+	// and retains trailing zeros, which we may need to restore.
+	if st.sharp && verb != 'b' {
+		digits := 0
+		switch verb {
+		case 'g', 'G', 'x':
+			digits = prec
+			// If no precision is set explicitly use a precision of 6.
+			if digits == -1 {
+				digits = 6
+			}
+		}
+
+		// Enough room for exponents of the form "e+123" or "p-1023".
+		var tailBuf [6]byte
+		tail := tailBuf[:0]
+
+		hasDecimalPoint := false
+		sawNonzeroDigit := false
+		// Skip the sign at num[start].
+		for i := start + 1; i < len(num); i++ {
+			switch num[i] {
+			case '.':
+				hasDecimalPoint = true
+			case 'p', 'P':
+				tail = append(tail, num[i:]...)
+				num = num[:i]
+			case 'e', 'E':
+				if verb != 'x' && verb != 'X' {
+					tail = append(tail, num[i:]...)
+					num = num[:i]
+					break
+				}
+				fallthrough
+			default:
+				if num[i] != '0' {
+					sawNonzeroDigit = true
+				}
+				// Count significant digits after the first non-zero digit.
+				if sawNonzeroDigit {
+					digits--
+				}
+			}
+		}
+		if !hasDecimalPoint {
+			// Leading digit 0 should contribute once to digits.
+			if len(num)-start == 2 && num[start+1] == '0' {
+				digits--
+			}
+			num = append(num, '.')
+		}
+		num = appendZeros(num, digits)
+		num = append(num, tail...)
+	}
+	// We want a sign if asked for and if the sign is not positive.
+	if st.plus || num[start] != '+' {
+		return st.pad(b[:start], num[start:], st.zero, true)
+	}
+	// No sign to show and the number is positive; just the unsigned number.
+	return st.pad(b[:start], num[start+1:], st.zero, false)
+}
+
+// pad appends num to b, padded to the width. If signed is set, num starts
+// with a sign, and zero padding goes after it. num may alias the tail of b.
+func (st *fmtState) pad(b, num []byte, zero, signed bool) []byte {
+	n := 0
+	if st.widPresent {
+		n = st.wid - len(num)
+	}
+	if n <= 0 {
+		return append(b, num...)
+	}
+	if st.minus {
+		// Zero padding is allowed only to the left.
+		b = append(b, num...)
+		for ; n > 0; n-- {
+			b = append(b, ' ')
+		}
+		return b
+	}
+	c := byte(' ')
+	if zero {
+		c = '0'
+	}
+	// Shift num right by n bytes and fill the gap.
+	b = extend(b, len(num)+n)
+	tail := b[len(b)-len(num):]
+	copy(tail, num)
+	gap := b[len(b)-len(num)-n : len(b)-len(num)]
+	if zero && signed {
+		gap[0] = tail[0]
+		gap, tail[0] = gap[1:], c
+	}
+	for i := range gap {
+		gap[i] = c
+	}
+	return b
+}
@@ -0,0 +1,157 @@
+// Copyright 2019 Caleb Spare
+//
+// The contents of this file may be used under the terms of the Apache License,
+// Version 2.0.
+//
+//    (See accompanying file LICENSE or copy at
+//     http://www.apache.org/licenses/LICENSE-2.0)
+//
+// Unless required by applicable law or agreed to in writing, this software
+// is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.
+
+package ryu
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"strings"
+	"testing"
+)
+
+var fmtFloatVerbs = []string{"v", "b", "e", "E", "f", "F", "g", "G", "x", "X", "d", "s", "é"}
+
+// fmtFloatFormats returns format strings covering every combination of flags
+// with a selection of widths and precisions for each verb.
+func fmtFloatFormats() []string {
+	var formats []string
+	for _, verb := range fmtFloatVerbs {
+		for flags := 0; flags < 1<<5; flags++ {
+			var flag string
+			for i, c := range "+- 0#" {
+				if flags&(1<<uint(i)) != 0 {
+					flag += string(c)
+				}
+			}
+			for _, wid := range []string{"", "0", "1", "8", "25"} {
+				for _, prec := range []string{"", ".", ".0", ".3", ".17"} {
+					formats = append(formats, "%"+flag+wid+prec+verb)
+				}
+			}
+		}
+	}
+	return formats
+}
+
+func TestFormatF64(t *testing.T) {
+	cases := append(genericTestCases, float64TestCases...)
+	cases = append(cases, 0.5, 1.25, 100, 2.5e-7, 1e21, 1e-320)
+	cases = append(cases, math.Copysign(0, -1), math.Inf(1), math.Inf(-1), math.NaN())
+	for _, format := range fmtFloatFormats() {
+		for _, f := range cases {
+			got := fmt.Sprintf(format, F64(f))
+			want := fmt.Sprintf(format, f)
+			want = strings.Replace(want, "(float64=", "(ryu.F64=", 1)
+			if got != want {
+				t.Errorf("Sprintf(%q, F64(%v)): got %q; want %q", format, f, got, want)
+			}
+		}
+	}
+}
+
+func TestFormatF32(t *testing.T) {
+	var cases []float32
+	for _, f := range append(genericTestCases, 0.5, 1.25, 100, 2.5e-7, 1e-40) {
+		cases = append(cases, float32(f))
+	}
+	cases = append(cases, float32(math.Inf(1)), float32(math.Inf(-1)), float32(math.NaN()))
+	for _, format := range fmtFloatFormats() {
+		for _, f := range cases {
+			got := fmt.Sprintf(format, F32(f))
+			want := fmt.Sprintf(format, f)
+			want = strings.Replace(want, "(float32=", "(ryu.F32=", 1)
+			if got != want {
+				t.Errorf("Sprintf(%q, F32(%v)): got %q; want %q", format, f, got, want)
+			}
+		}
+	}
+}
+
+func TestFormatF64Random(t *testing.T) {
+	formats := fmtFloatFormats()
+	for i := 0; i < 20000; i++ {
+		f := math.Float64frombits(rand.Uint64())
+		format := formats[rand.Intn(len(formats))]
+		// strconv's shortest output had bugs before Go 1.17
+		// (https://golang.org/issue/29491), so always give a precision.
+		if !strings.Contains(format, ".") {
+			format = format[:len(format)-1] + ".10" + format[len(format)-1:]
+		}
+		got := fmt.Sprintf(format, F64(f))
+		want := fmt.Sprintf(format, f)
+		want = strings.Replace(want, "(float64=", "(ryu.F64=", 1)
+		if got != want {
+			t.Errorf("Sprintf(%q, F64(%v)): got %q; want %q", format, f, got, want)
+		}
+	}
+}
+
+func TestFormatFStarArgs(t *testing.T) {
+	for _, tt := range []struct {
+		format string
+		args   []interface{}
+	}{
+		{"%*f", []interface{}{-12, 1.5}},
+		{"%0*f", []interface{}{-12, -1.5}},
+		{"%*.*e", []interface{}{15, 3, 1.5}},
+		{"%[2]*[1]g|%[1]v", []interface{}{-2.25, 10}},
+	} {
+		want := fmt.Sprintf(tt.format, tt.args...)
+		args := make([]interface{}, len(tt.args))
+		for i, arg := range tt.args {
+			if f, ok := arg.(float64); ok {
+				arg = F64(f)
+			}
+			args[i] = arg
+		}
+		if got := fmt.Sprintf(tt.format, args...); got != want {
+			t.Errorf("Sprintf(%q, %v): got %q; want %q", tt.format, tt.args, got, want)
+		}
+	}
+}
+
+func TestFStringer(t *testing.T) {
+	for _, f := range append(genericTestCases, math.Inf(-1), math.NaN()) {
+		if got, want := F64(f).String(), fmt.Sprint(f); got != want {
+			t.Errorf("F64(%v).String(): got %q; want %q", f, got, want)
+		}
+		if got, want := F32(f).String(), fmt.Sprint(float32(f)); got != want {
+			t.Errorf("F32(%v).String(): got %q; want %q", f, got, want)
+		}
+	}
+}
+
+func BenchmarkSprintfF64(b *testing.B) {
+	for _, bb := range []struct {
+		name   string
+		format string
+	}{
+		{"V", "%v"},
+		{"Prec", "%.3e"},
+		{"Width", "%8.2f"},
+	} {
+		b.Run(bb.name, func(b *testing.B) {
+			b.Run("fmt", func(b *testing.B) {
+				for i := 0; i < b.N; i++ {
+					sink = fmt.Sprintf(bb.format, 123.456789)
+				}
+			})
+			b.Run("ryu", func(b *testing.B) {
+				for i := 0; i < b.N; i++ {
+					sink = fmt.Sprintf(bb.format, F64(123.456789))
+				}
+			})
+		})
+	}
+}
@@ -0,0 +1,228 @@
+// Copyright 2019 Caleb Spare
+//
+// The contents of this file may be used under the terms of the Apache License,
+// Version 2.0.
+//
+//    (See accompanying file LICENSE or copy at
+//     http://www.apache.org/licenses/LICENSE-2.0)
+//
+// Unless required by applicable law or agreed to in writing, this software
+// is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.
+
+package ryu
+
+import (
+	"math"
+)
+
+// A SignPolicy specifies when a sign is printed.
+type SignPolicy uint8
+
+const (
+	// SignDefault selects the default policy of an Options field.
+	SignDefault SignPolicy = iota
+	// SignNegative prints '-' for negative values and nothing otherwise.
+	SignNegative
+	// SignAlways prints '-' for negative values and '+' otherwise.
+	SignAlways
+)
+
+// Options configures the output of a Formatter. The zero Options formats
+// numbers exactly like FormatFloat64 and FormatFloat32.
+type Options struct {
+	// ExpChar introduces the exponent. If it is zero, 'e' is used.
+	ExpChar byte
+	// Sign is the policy for the sign of the number.
+	// SignDefault means SignNegative.
+	Sign SignPolicy
+	// ExpSign is the policy for the sign of the exponent.
+	// SignDefault means SignAlways.
+	ExpSign SignPolicy
+	// MinExpDigits is the minimum number of exponent digits; shorter
+	// exponents are padded with zeros. If it is zero, 2 is used. It may be
+	// at most 10, which keeps every formatted number (at most 17 digits, a
+	// point, a sign, and a signed exponent) within 31 bytes, so that
+	// FormatFloat64 and FormatFloat32 need no more than a small stack
+	// buffer.
+	MinExpDigits int
+	// ForcePoint prints a decimal point even if there is only one
+	// digit, as in "1.0e+00".
+	ForcePoint bool
+	// Inf, NegInf, and NaN are printed for positive infinity, negative
+	// infinity, and NaN. Empty strings mean "+Inf", "-Inf", and "NaN".
+	Inf, NegInf, NaN string
+	// UnsignedZero prints negative zero like positive zero.
+	UnsignedZero bool
+}
+
+// A Formatter formats floating point numbers like AppendFloat64 and
+// AppendFloat32 (the shortest representation that round-trips, in
+// exponent notation), with the spelling of the output configured by
+// Options. The methods of a Formatter do not allocate except to grow the
+// buffer, and a Formatter may be used concurrently.
+type Formatter struct {
+	expChar      byte
+	plus         bool // print '+' for nonnegative numbers
+	expPlus      bool // print '+' for nonnegative exponents
+	minExpDigits int
+	forcePoint   bool
+	inf          string
+	negInf       string
+	nan          string
+	unsignedZero bool
+}
+
+// NewFormatter returns a Formatter configured by opts.
+// It panics if MinExpDigits is negative or greater than 10.
+func NewFormatter(opts Options) *Formatter {
+	if opts.MinExpDigits < 0 || opts.MinExpDigits > 10 {
+		panic("ryu: Options.MinExpDigits out of range")
+	}
+	f := &Formatter{
+		expChar:      opts.ExpChar,
+		plus:         opts.Sign == SignAlways,
+		expPlus:      opts.ExpSign != SignNegative,
+		minExpDigits: opts.MinExpDigits,
+		forcePoint:   opts.ForcePoint,
+		inf:          opts.Inf,
+		negInf:       opts.NegInf,
+		nan:          opts.NaN,
+		unsignedZero: opts.UnsignedZero,
+	}
+	if f.expChar == 0 {
+		f.expChar = 'e'
+	}
+	if f.minExpDigits == 0 {
+		f.minExpDigits = 2
+	}
+	if f.inf == "" {
+		f.inf = "+Inf"
+	}
+	if f.negInf == "" {
+		f.negInf = "-Inf"
+	}
+	if f.nan == "" {
+		f.nan = "NaN"
+	}
+	return f
+}
+
+// FormatFloat64 converts the 64-bit floating point number x to a string.
+func (f *Formatter) FormatFloat64(x float64) string {
+	var buf [32]byte
+	return string(f.AppendFloat64(buf[:0], x))
+}
+
+// AppendFloat64 appends the string form of the 64-bit floating point number
+// x to b and returns the extended buffer.
+func (f *Formatter) AppendFloat64(b []byte, x float64) []byte {
+	u := math.Float64bits(x)
+	neg := u>>(mantBits64+expBits64) != 0
+	mant := u & (uint64(1)<<mantBits64 - 1)
+	exp := (u >> mantBits64) & (uint64(1)<<expBits64 - 1)
+
+	if exp == uint64(1)<<expBits64-1 || (exp == 0 && mant == 0) {
+		return f.appendSpecial(b, neg, exp == 0, mant == 0)
+	}
+
+	d, ok := float64ToDecimalExactInt(mant, exp)
+	if !ok {
+		d = float64ToDecimal(mant, exp)
+	}
+	return f.appendDecimal(b, d.m, d.e, neg)
+}
+
+// FormatFloat32 converts the 32-bit floating point number x to a string.
+func (f *Formatter) FormatFloat32(x float32) string {
+	var buf [32]byte
+	return string(f.AppendFloat32(buf[:0], x))
+}
+
+// AppendFloat32 appends the string form of the 32-bit floating point number
+// x to b and returns the extended buffer.
+func (f *Formatter) AppendFloat32(b []byte, x float32) []byte {
+	u := math.Float32bits(x)
+	neg := u>>(mantBits32+expBits32) != 0
+	mant := u & (uint32(1)<<mantBits32 - 1)
+	exp := (u >> mantBits32) & (uint32(1)<<expBits32 - 1)
+
+	if exp == uint32(1)<<expBits32-1 || (exp == 0 && mant == 0) {
+		return f.appendSpecial(b, neg, exp == 0, mant == 0)
+	}
+
+	d, ok := float32ToDecimalExactInt(mant, exp)
+	if !ok {
+		d = float32ToDecimal(mant, exp)
+	}
+	return f.appendDecimal(b, uint64(d.m), d.e, neg)
+}
+
+func (f *Formatter) appendSpecial(b []byte, neg, expZero, mantZero bool) []byte {
+	switch {
+	case !mantZero:
+		return append(b, f.nan...)
+	case !expZero && neg:
+		return append(b, f.negInf...)
+	case !expZero:
+		return append(b, f.inf...)
+	}
+	return f.appendDecimal(b, 0, 0, neg && !f.unsignedZero)
+}
+
+// appendDecimal appends m * 10^e, where m has no trailing zeros (or is zero).
+func (f *Formatter) appendDecimal(b []byte, m uint64, e int32, neg bool) []byte {
+	if neg {
+		b = append(b, '-')
+	} else if f.plus {
+		b = append(b, '+')
+	}
+
+	outLen := 1
+	if m != 0 {
+		outLen = decimalLen64(m)
+	}
+	n := len(b)
+	switch {
+	case outLen > 1:
+		// Print the digits one byte to the right and then
+		// move the first one left to make room for the '.'.
+		b = extend(b, outLen+1)
+		writeDigits64(b[n+1:], m)
+		b[n] = b[n+1]
+		b[n+1] = '.'
+	case f.forcePoint:
+		b = append(b, '0'+byte(m), '.', '0')
+	default:
+		b = append(b, '0'+byte(m))
+	}
+
+	b = append(b, f.expChar)
+	exp := e + int32(outLen) - 1
+	if m == 0 {
+		exp = 0
+	}
+	if exp < 0 {
+		b = append(b, '-')
+		exp = -exp
+	} else if f.expPlus {
+		b = append(b, '+')
+	}
+	// The exponent has at most 3 digits.
+	expLen := 1
+	if exp >= 100 {
+		expLen = 3
+	} else if exp >= 10 {
+		expLen = 2
+	}
+	for i := expLen; i < f.minExpDigits; i++ {
+		b = append(b, '0')
+	}
+	n = len(b)
+	b = extend(b, expLen)
+	for i := len(b) - 1; i >= n; i-- {
+		b[i] = '0' + byte(exp%10)
+		exp /= 10
+	}
+	return b
+}
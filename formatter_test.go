@@ -0,0 +1,116 @@
+// Copyright 2019 Caleb Spare
+//
+// The contents of this file may be used under the terms of the Apache License,
+// Version 2.0.
+//
+//    (See accompanying file LICENSE or copy at
+//     http://www.apache.org/licenses/LICENSE-2.0)
+//
+// Unless required by applicable law or agreed to in writing, this software
+// is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.
+
+package ryu
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+)
+
+func TestFormatterDefault(t *testing.T) {
+	f := NewFormatter(Options{})
+	cases := append(genericTestCases, float64TestCases...)
+	for _, x := range append(cases, layoutTestCases...) {
+		if got, want := f.FormatFloat64(x), FormatFloat64(x); got != want {
+			t.Errorf("FormatFloat64(%g): got %q; want %q", x, got, want)
+		}
+		x32 := float32(x)
+		if got, want := f.FormatFloat32(x32), FormatFloat32(x32); got != want {
+			t.Errorf("FormatFloat32(%g): got %q; want %q", x32, got, want)
+		}
+	}
+	for i := 0; i < 10000; i++ {
+		x := math.Float64frombits(rand.Uint64())
+		if got, want := f.FormatFloat64(x), FormatFloat64(x); got != want {
+			t.Fatalf("FormatFloat64(%g): got %q; want %q", x, got, want)
+		}
+	}
+}
+
+func TestFormatter(t *testing.T) {
+	negZero := math.Copysign(0, -1)
+	for _, tt := range []struct {
+		opts Options
+		f    float64
+		want string
+	}{
+		{Options{ExpChar: 'E'}, 1.5, "1.5E+00"},
+		{Options{ExpChar: 'E'}, math.Inf(1), "+Inf"},
+		{Options{Sign: SignAlways}, 1.5, "+1.5e+00"},
+		{Options{Sign: SignAlways}, -1.5, "-1.5e+00"},
+		{Options{Sign: SignAlways}, 0, "+0e+00"},
+		{Options{Sign: SignNegative}, 1.5, "1.5e+00"},
+		{Options{ExpSign: SignNegative}, 1.5e10, "1.5e10"},
+		{Options{ExpSign: SignNegative}, 1.5e-10, "1.5e-10"},
+		{Options{ExpSign: SignAlways}, 1.5e10, "1.5e+10"},
+		{Options{MinExpDigits: 1}, 1e5, "1e+5"},
+		{Options{MinExpDigits: 1}, 1e-300, "1e-300"},
+		{Options{MinExpDigits: 3}, 1e5, "1e+005"},
+		{Options{MinExpDigits: 3}, 0, "0e+000"},
+		{
+			Options{Sign: SignAlways, MinExpDigits: 10},
+			-1.2345678901234568e-300,
+			"-1.2345678901234568e-0000000300", // the longest possible output
+		},
+		{Options{ForcePoint: true}, 1, "1.0e+00"},
+		{Options{ForcePoint: true}, 0, "0.0e+00"},
+		{Options{ForcePoint: true}, 1.25, "1.25e+00"},
+		{Options{Inf: "Infinity", NegInf: "-Infinity", NaN: "nan"}, math.Inf(1), "Infinity"},
+		{Options{Inf: "Infinity", NegInf: "-Infinity", NaN: "nan"}, math.Inf(-1), "-Infinity"},
+		{Options{Inf: "Infinity", NegInf: "-Infinity", NaN: "nan"}, math.NaN(), "nan"},
+		{Options{}, negZero, "-0e+00"},
+		{Options{UnsignedZero: true}, negZero, "0e+00"},
+		{Options{UnsignedZero: true, Sign: SignAlways}, negZero, "+0e+00"},
+		{
+			Options{ExpChar: 'E', ExpSign: SignNegative, MinExpDigits: 1, ForcePoint: true},
+			-5e-324,
+			"-5.0E-324",
+		},
+		{
+			Options{ExpChar: 'E', ExpSign: SignNegative, MinExpDigits: 1, ForcePoint: true},
+			123456789,
+			"1.23456789E8",
+		},
+	} {
+		if got := NewFormatter(tt.opts).FormatFloat64(tt.f); got != tt.want {
+			t.Errorf("%+v: FormatFloat64(%g): got %q; want %q", tt.opts, tt.f, got, tt.want)
+		}
+	}
+}
+
+func TestFormatterAllocs(t *testing.T) {
+	f := NewFormatter(Options{ExpChar: 'E', MinExpDigits: 3, ForcePoint: true})
+	buf := make([]byte, 0, 64)
+	allocs := testing.AllocsPerRun(1000, func() {
+		f.AppendFloat64(buf, 1.2345e-67)
+		f.AppendFloat32(buf, 1.2345e-7)
+		f.AppendFloat64(buf, math.Inf(1))
+	})
+	if allocs != 0 {
+		t.Errorf("got %.1f allocations per run; want 0", allocs)
+	}
+}
+
+func BenchmarkFormatter(b *testing.B) {
+	fm := NewFormatter(Options{ExpChar: 'E', MinExpDigits: 3})
+	for _, f := range append(benchCases, benchCases64...) {
+		b.Run(FormatFloat64(f), func(b *testing.B) {
+			var buf []byte
+			for i := 0; i < b.N; i++ {
+				buf = fm.AppendFloat64(buf[:0], f)
+			}
+			sinkb = buf
+		})
+	}
+}
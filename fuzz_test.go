@@ -0,0 +1,49 @@
+// Copyright 2019 Caleb Spare
+//
+// The contents of this file may be used under the terms of the Apache License,
+// Version 2.0.
+//
+//    (See accompanying file LICENSE or copy at
+//     http://www.apache.org/licenses/LICENSE-2.0)
+//
+// Unless required by applicable law or agreed to in writing, this software
+// is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.
+
+package ryu
+
+import (
+	"math"
+	"testing"
+
+	"github.com/cespare/ryu/verify"
+)
+
+// The fuzz targets check with the verify package that the output parses
+// back to the input, that no shorter decimal does, and that the output is
+// the closest among the decimals with as many digits that do.
+
+func FuzzAppendFloat64(f *testing.F) {
+	cases := append(genericTestCases, float64TestCases...)
+	for _, x := range append(cases, strconvBugTestCases...) {
+		f.Add(math.Float64bits(x))
+	}
+	f.Fuzz(func(t *testing.T, bits uint64) {
+		x := math.Float64frombits(bits)
+		if err := verify.Check64(string(AppendFloat64(nil, x)), x); err != nil {
+			t.Fatal(err)
+		}
+	})
+}
+
+func FuzzAppendFloat32(f *testing.F) {
+	for _, x := range append(genericTestCases, float64TestCases...) {
+		f.Add(math.Float32bits(float32(x)))
+	}
+	f.Fuzz(func(t *testing.T, bits uint32) {
+		x := math.Float32frombits(bits)
+		if err := verify.Check32(string(AppendFloat32(nil, x)), x); err != nil {
+			t.Fatal(err)
+		}
+	})
+}
@@ -0,0 +1,42 @@
+// Copyright 2019 Caleb Spare
+//
+// The contents of this file may be used under the terms of the Apache License,
+// Version 2.0.
+//
+//    (See accompanying file LICENSE or copy at
+//     http://www.apache.org/licenses/LICENSE-2.0)
+//
+// Unless required by applicable law or agreed to in writing, this software
+// is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.
+
+package ryu
+
+import "unsafe"
+
+// Float is the set of types that Append and Format accept: any type whose
+// underlying type is float32 or float64.
+type Float interface {
+	~float32 | ~float64
+}
+
+// Format converts the floating point number f to a string in the shortest
+// representation. It is FormatFloat32 if the underlying type of T is float32
+// and FormatFloat64 otherwise.
+func Format[T Float](f T) string {
+	// The compiler instantiates separate code for float32 and float64 type
+	// arguments, so this condition is constant and the call is direct.
+	if unsafe.Sizeof(f) == 4 {
+		return FormatFloat32(float32(f))
+	}
+	return FormatFloat64(float64(f))
+}
+
+// Append appends the string form of f, as generated by Format, to b and
+// returns the extended buffer.
+func Append[T Float](b []byte, f T) []byte {
+	if unsafe.Sizeof(f) == 4 {
+		return AppendFloat32(b, float32(f))
+	}
+	return AppendFloat64(b, float64(f))
+}
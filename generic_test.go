@@ -0,0 +1,63 @@
+// Copyright 2019 Caleb Spare
+//
+// The contents of this file may be used under the terms of the Apache License,
+// Version 2.0.
+//
+//    (See accompanying file LICENSE or copy at
+//     http://www.apache.org/licenses/LICENSE-2.0)
+//
+// Unless required by applicable law or agreed to in writing, this software
+// is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.
+
+package ryu
+
+import (
+	"testing"
+)
+
+type (
+	celsius float64
+	score   float32
+)
+
+func TestGeneric(t *testing.T) {
+	for _, f := range genericTestCases {
+		if got, want := Format(f), FormatFloat64(f); got != want {
+			t.Errorf("Format(float64(%v)): got %q; want %q", f, got, want)
+		}
+		if got, want := Format(celsius(f)), FormatFloat64(f); got != want {
+			t.Errorf("Format(celsius(%v)): got %q; want %q", f, got, want)
+		}
+		f32 := float32(f)
+		if got, want := Format(f32), FormatFloat32(f32); got != want {
+			t.Errorf("Format(float32(%v)): got %q; want %q", f32, got, want)
+		}
+		if got, want := Format(score(f32)), FormatFloat32(f32); got != want {
+			t.Errorf("Format(score(%v)): got %q; want %q", f32, got, want)
+		}
+		if got, want := string(Append([]byte("x"), score(f32))), "x"+FormatFloat32(f32); got != want {
+			t.Errorf("Append(score(%v)): got %q; want %q", f32, got, want)
+		}
+		if got, want := string(Append([]byte("x"), celsius(f))), "x"+FormatFloat64(f); got != want {
+			t.Errorf("Append(celsius(%v)): got %q; want %q", f, got, want)
+		}
+	}
+}
+
+func BenchmarkAppendGeneric(b *testing.B) {
+	b.Run("32", func(b *testing.B) {
+		var buf []byte
+		for i := 0; i < b.N; i++ {
+			buf = Append(buf[:0], score(123.45))
+		}
+		sinkb = buf
+	})
+	b.Run("64", func(b *testing.B) {
+		var buf []byte
+		for i := 0; i < b.N; i++ {
+			buf = Append(buf[:0], celsius(123.45))
+		}
+		sinkb = buf
+	})
+}
@@ -1,3 +1,3 @@
 module github.com/cespare/ryu
 
-go 1.12
+go 1.18
@@ -0,0 +1,114 @@
+// Copyright 2019 Caleb Spare
+//
+// The contents of this file may be used under the terms of the Apache License,
+// Version 2.0.
+//
+//    (See accompanying file LICENSE or copy at
+//     http://www.apache.org/licenses/LICENSE-2.0)
+//
+// Unless required by applicable law or agreed to in writing, this software
+// is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.
+
+package ryu
+
+import (
+	"math"
+	"reflect"
+	"unsafe"
+)
+
+// FormatFloat64JS converts the 64-bit floating point number f to a string
+// the way JavaScript does, as specified by Number::toString in ECMA-262:
+// numbers with a decimal exponent between -7 and 21 (exclusive) are printed
+// in positional notation and others in exponent notation, like "1e+21" or
+// "1.5e-7". Negative zero is printed as "0", and the infinities and NaN are
+// printed as "Infinity", "-Infinity", and "NaN".
+//
+// The digits are the same shortest digits used by FormatFloat64, which are
+// those required by ECMA-262.
+func FormatFloat64JS(f float64) string {
+	b := make([]byte, 0, 25)
+	b = AppendFloat64JS(b, f)
+
+	// Convert the output to a string without copying.
+	var s string
+	sh := (*reflect.StringHeader)(unsafe.Pointer(&s))
+	sh.Data = uintptr(unsafe.Pointer(&b[0]))
+	sh.Len = len(b)
+	return s
+}
+
+// AppendFloat64JS appends the string form of the 64-bit floating point
+// number f, as generated by FormatFloat64JS, to b and returns the extended
+// buffer.
+func AppendFloat64JS(b []byte, f float64) []byte {
+	u := math.Float64bits(f)
+	neg := u>>(mantBits64+expBits64) != 0
+	mant := u & (uint64(1)<<mantBits64 - 1)
+	exp := (u >> mantBits64) & (uint64(1)<<expBits64 - 1)
+
+	switch {
+	case exp == uint64(1)<<expBits64-1:
+		if mant != 0 {
+			return append(b, "NaN"...)
+		}
+		if neg {
+			return append(b, "-Infinity"...)
+		}
+		return append(b, "Infinity"...)
+	case exp == 0 && mant == 0:
+		return append(b, '0')
+	}
+
+	d, ok := float64ToDecimalExactInt(mant, exp)
+	if !ok {
+		d = float64ToDecimal(mant, exp)
+	}
+	if neg {
+		b = append(b, '-')
+	}
+
+	// In the terms of ECMA-262, the value is s * 10^(n-k),
+	// where s has k digits.
+	var digits [17]byte
+	k := decimalLen64(d.m)
+	writeDigits64(digits[:k], d.m)
+	s := digits[:k]
+	n := int(d.e) + k
+
+	switch {
+	case k <= n && n <= 21:
+		b = append(b, s...)
+		return appendZeros(b, n-k)
+	case 0 < n && n <= 21:
+		b = append(b, s[:n]...)
+		b = append(b, '.')
+		return append(b, s[n:]...)
+	case -6 < n && n <= 0:
+		b = append(b, '0', '.')
+		b = appendZeros(b, -n)
+		return append(b, s...)
+	}
+
+	b = append(b, s[0])
+	if k > 1 {
+		b = append(b, '.')
+		b = append(b, s[1:]...)
+	}
+	b = append(b, 'e')
+	e := n - 1
+	if e < 0 {
+		b = append(b, '-')
+		e = -e
+	} else {
+		b = append(b, '+')
+	}
+	if e >= 100 {
+		b = append(b, '0'+byte(e/100))
+	}
+	if e >= 10 {
+		b = append(b, '0'+byte(e/10%10))
+	}
+	return append(b, '0'+byte(e%10))
+}
@@ -0,0 +1,73 @@
+// Copyright 2019 Caleb Spare
+//
+// The contents of this file may be used under the terms of the Apache License,
+// Version 2.0.
+//
+//    (See accompanying file LICENSE or copy at
+//     http://www.apache.org/licenses/LICENSE-2.0)
+//
+// Unless required by applicable law or agreed to in writing, this software
+// is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.
+
+package ryu
+
+import "math"
+
+// An UnsupportedValueError is returned by AppendJSONFloat64 and
+// AppendJSONFloat32 for numbers that cannot be represented in JSON (NaN and
+// the infinities). Its message is the same as that of the
+// json.UnsupportedValueError returned by json.Marshal for these values.
+type UnsupportedValueError struct {
+	Str string // "NaN", "+Inf", or "-Inf"
+}
+
+func (e *UnsupportedValueError) Error() string {
+	return "json: unsupported value: " + e.Str
+}
+
+// AppendJSONFloat64 appends the JSON encoding of the 64-bit floating point
+// number f to b and returns the extended buffer. The output is the same as
+// that of json.Marshal: the shortest representation in positional notation,
+// or in exponent notation (with at least one exponent digit, as in "1e-7")
+// if the absolute value of f is less than 1e-6 or at least 1e21.
+//
+// If f is NaN or an infinity, AppendJSONFloat64 returns b unchanged and an
+// *UnsupportedValueError.
+func AppendJSONFloat64(b []byte, f float64) ([]byte, error) {
+	if math.IsNaN(f) || math.IsInf(f, 0) {
+		return b, &UnsupportedValueError{Str: string(AppendFloat64General(nil, f))}
+	}
+	abs := math.Abs(f)
+	if abs != 0 && (abs < 1e-6 || abs >= 1e21) {
+		return trimExpZero(AppendFloat64(b, f)), nil
+	}
+	return AppendFloat64Fixed(b, f), nil
+}
+
+// AppendJSONFloat32 is like AppendJSONFloat64 for float32s. The output is
+// the same as that of json.Marshal for a float32.
+func AppendJSONFloat32(b []byte, f float32) ([]byte, error) {
+	if f != f || f > math.MaxFloat32 || f < -math.MaxFloat32 {
+		return b, &UnsupportedValueError{Str: string(AppendFloat32General(nil, f))}
+	}
+	abs := f
+	if abs < 0 {
+		abs = -abs
+	}
+	if abs != 0 && (abs < 1e-6 || abs >= 1e21) {
+		return trimExpZero(AppendFloat32(b, f)), nil
+	}
+	return AppendFloat32Fixed(b, f), nil
+}
+
+// trimExpZero rewrites a two-digit negative exponent with a leading zero
+// at the end of b, such as e-07, to a single digit (e-7).
+func trimExpZero(b []byte) []byte {
+	n := len(b)
+	if n >= 4 && b[n-4] == 'e' && b[n-3] == '-' && b[n-2] == '0' {
+		b[n-2] = b[n-1]
+		b = b[:n-1]
+	}
+	return b
+}
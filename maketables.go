@@ -22,9 +22,10 @@
 // That source code is licensed under Apache 2.0 and this code is derivative
 // work thereof.
 
-// +build ignore
+//go:build ignore
 
-// This program generates tables.go.
+// This program generates tables.go, tables64.go, tables64_small.go,
+// tables_prec.go, and tables128.go.
 
 package main
 
@@ -62,7 +63,7 @@ package ryu
 
 const (
 	posTableSize32   = 47
-	negTableSize32   = 31
+	negTableSize32   = 36 // 31 is enough for float32; bfloat16 needs 36
 	pow5NumBits32    = 61 // max 63
 	pow5InvNumBits32 = 59 // max 63
 
@@ -70,6 +71,19 @@ const (
 	negTableSize64   = 291 + 1
 	pow5NumBits64    = 121 // max 127
 	pow5InvNumBits64 = 122 // max 127
+	pow5TableSize64  = 26  // used by the ryu_small tables
+
+	// These are used by the fixed-precision (Ryu printf) tables.
+	pow10AdditionalBits = 120
+	additionalBits2     = 120
+	maxExp64            = 1<<11 - 2 - 1023 - 52 // largest e2 of a float64
+	minExp64            = 1 - 1023 - 52         // smallest e2 of a float64
+
+	// These are used by the generic 128-bit tables.
+	pow5TableSize128  = 56 // 5^55 is the largest power of 5 that fits in 128 bits
+	pow5NumBits128    = 249
+	pow5InvNumBits128 = 249
+	pow5Index128      = 89 * pow5TableSize128 // bound on the power of 5 needed
 )
 
 func main() {
@@ -106,45 +120,402 @@ func main() {
 	}
 	fmt.Fprintln(b, "\n}")
 
-	mask64 := big.NewInt(1)
-	mask64.Lsh(mask64, 64)
-	mask64.Sub(mask64, big.NewInt(1))
-
+	fmt.Fprintf(b, "const posTableSize64 = %d\n", posTableSize64)
+	fmt.Fprintf(b, "const negTableSize64 = %d\n", negTableSize64)
 	fmt.Fprintf(b, "const pow5NumBits64 = %d\n", pow5NumBits64)
+	fmt.Fprintf(b, "const pow5InvNumBits64 = %d\n", pow5InvNumBits64)
+
+	writeSource("tables.go", b.Bytes())
+	writeSource("tables64.go", tables64())
+	writeSource("tables64_small.go", tables64Small())
+	writeSource("tables_prec.go", precTables())
+	writeSource("tables128.go", tables128())
+}
+
+// taggedHeader returns header with a build constraint for tag.
+func taggedHeader(tag string) []byte {
+	constraint := fmt.Sprintf("\n//go:build %s\n\npackage ryu", tag)
+	return bytes.Replace(header, []byte("\npackage ryu"), []byte(constraint), 1)
+}
+
+func pow5(i int) *big.Int {
+	return new(big.Int).Exp(big.NewInt(5), big.NewInt(int64(i)), nil)
+}
+
+// pow5Split64 returns 5^i with pow5NumBits64 bits (rounded down).
+func pow5Split64(i int) *big.Int {
+	v := pow5(i)
+	rsh(v, v.BitLen()-pow5NumBits64)
+	return v
+}
+
+// pow5InvSplit64 returns floor(2^(floor(log_2 5^i) + pow5InvNumBits64) / 5^i).
+// The full table stores this plus 1.
+func pow5InvSplit64(i int) *big.Int {
+	v := pow5(i)
+	inv := big.NewInt(1)
+	// We want floor(log_2 5^i) here, which is v.BitLen() - 1.
+	rsh(inv, -(v.BitLen() - 1 + pow5InvNumBits64))
+	return inv.Quo(inv, v)
+}
+
+func printUint128(b *bytes.Buffer, v *big.Int) {
+	mask64 := new(big.Int).Sub(new(big.Int).Lsh(big.NewInt(1), 64), big.NewInt(1))
+	if v.BitLen() > 128 {
+		log.Fatal("table entry does not fit into 128 bits")
+	}
+	lo := new(big.Int).And(v, mask64)
+	hi := new(big.Int).Rsh(v, 64)
+	fmt.Fprintf(b, "{%d, %d},\n", lo.Uint64(), hi.Uint64())
+}
+
+// tables64 generates the full float64 tables, which are used unless the
+// ryu_small build tag is set.
+func tables64() []byte {
+	b := bytes.NewBuffer(taggedHeader("!ryu_small"))
+
 	fmt.Fprintln(b, "var pow5Split64 = [...]uint128{")
-	for i := int64(0); i < posTableSize64; i++ {
-		pow5 := big.NewInt(5)
-		pow5.Exp(pow5, big.NewInt(i), nil)
-		shift := pow5.BitLen() - pow5NumBits64
-		rsh(pow5, shift)
-		lo := new(big.Int).And(pow5, mask64)
-		hi := new(big.Int).Rsh(pow5, 64)
-		fmt.Fprintf(b, "{%d, %d},\n", lo.Uint64(), hi.Uint64())
+	for i := 0; i < posTableSize64; i++ {
+		printUint128(b, pow5Split64(i))
 	}
 	fmt.Fprintln(b, "\n}")
 
-	fmt.Fprintf(b, "const pow5InvNumBits64 = %d\n", pow5InvNumBits64)
 	fmt.Fprintln(b, "var pow5InvSplit64 = [...]uint128{")
-	for i := int64(0); i < negTableSize64; i++ {
-		pow5 := big.NewInt(5)
-		pow5.Exp(pow5, big.NewInt(i), nil)
-		// We want floor(log_2 5^q) here, which is pow5.BitLen() - 1.
-		shift := pow5.BitLen() - 1 + pow5InvNumBits64
+	for i := 0; i < negTableSize64; i++ {
+		printUint128(b, new(big.Int).Add(pow5InvSplit64(i), big.NewInt(1)))
+	}
+	fmt.Fprintln(b, "\n}")
+
+	return b.Bytes()
+}
+
+// tables64Small generates the compact float64 tables used with the
+// ryu_small build tag (RYU_OPTIMIZE_SIZE in the C library).
+//
+// Only every pow5TableSize64th entry of the full tables is stored
+// (pow5Split64Small and pow5InvSplit64Small, both rounded down). The
+// others are computed at run time by multiplying a stored entry by an
+// exact power of 5 from pow5Table64 and shifting, in the same way as for
+// the generic 128-bit tables. pow5Offsets64 and pow5InvOffsets64 record,
+// with 2 bits per power, the amount that must be added to the result to
+// get exactly the value of the full table.
+func tables64Small() []byte {
+	b := bytes.NewBuffer(taggedHeader("ryu_small"))
+
+	fmt.Fprintf(b, "const pow5TableSize64 = %d\n", pow5TableSize64)
+	fmt.Fprintln(b, "var pow5Table64 = [...]uint64{")
+	for i := 0; i < pow5TableSize64; i++ {
+		fmt.Fprintf(b, "%d,", pow5(i).Uint64())
+		if i%4 == 3 {
+			fmt.Fprintln(b)
+		}
+	}
+	fmt.Fprintln(b, "\n}")
+
+	fmt.Fprintln(b, "var pow5Split64Small = [...]uint128{")
+	for base := 0; base*pow5TableSize64 < posTableSize64; base++ {
+		printUint128(b, pow5Split64(base*pow5TableSize64))
+	}
+	fmt.Fprintln(b, "}")
+
+	fmt.Fprintln(b, "var pow5InvSplit64Small = [...]uint128{")
+	for base := 0; base*pow5TableSize64 < negTableSize64+pow5TableSize64-1; base++ {
+		printUint128(b, pow5InvSplit64(base*pow5TableSize64))
+	}
+	fmt.Fprintln(b, "}")
+
+	// The run-time computation for 5^i, where i is not a multiple of
+	// pow5TableSize64, is
+	//
+	//   floor(5^offset * split(base) / 2^delta) + corr
+	//
+	// where base (rounded down for 5^i and up for 5^-i) and offset are
+	// such that 5^i = 5^(base*pow5TableSize64) * 5^offset, and delta is
+	// the difference of the bit lengths of the two powers.
+	offsets := func(name string, n int, exact func(i int) *big.Int, approx func(i int) *big.Int) {
+		var packed []uint32
+		for i := 0; i < n; i++ {
+			if i%16 == 0 {
+				packed = append(packed, 0)
+			}
+			if i%pow5TableSize64 == 0 {
+				continue
+			}
+			corr := new(big.Int).Sub(exact(i), approx(i))
+			if corr.Sign() < 0 || corr.Cmp(big.NewInt(3)) > 0 {
+				log.Fatalf("%s: correction for 5^%d out of range: %s", name, i, corr)
+			}
+			packed[i/16] |= uint32(corr.Uint64()) << (2 * uint(i%16))
+		}
+		fmt.Fprintf(b, "var %s = [...]uint32{\n", name)
+		for i, w := range packed {
+			fmt.Fprintf(b, "%#08x,", w)
+			if i%8 == 7 {
+				fmt.Fprintln(b)
+			}
+		}
+		fmt.Fprintln(b, "\n}")
+	}
+	offsets("pow5Offsets64", posTableSize64, pow5Split64, func(i int) *big.Int {
+		base2 := i / pow5TableSize64 * pow5TableSize64
+		delta := pow5(i).BitLen() - pow5(base2).BitLen()
+		v := new(big.Int).Mul(pow5(i-base2), pow5Split64(base2))
+		return v.Rsh(v, uint(delta))
+	})
+	offsets("pow5InvOffsets64", negTableSize64, func(i int) *big.Int {
+		return new(big.Int).Add(pow5InvSplit64(i), big.NewInt(1))
+	}, func(i int) *big.Int {
+		base2 := (i + pow5TableSize64 - 1) / pow5TableSize64 * pow5TableSize64
+		delta := pow5(base2).BitLen() - pow5(i).BitLen()
+		v := new(big.Int).Mul(pow5(base2-i), pow5InvSplit64(base2))
+		return v.Rsh(v, uint(delta))
+	})
+
+	return b.Bytes()
+}
+
+// tables128 generates the tables used by the generic 128-bit algorithm.
+//
+// Storing a 256-bit entry for every power of 5 that a binary128 needs would
+// take hundreds of kilobytes, so (as in the C library's generic_128.c) only
+// every 56th power is stored with full precision. The powers in between are
+// computed at run time by multiplying a stored entry by an exact power of 5
+// from pow5Table128 and shifting. The result of that may be a little too
+// small; pow5Errors128 and pow5InvErrors128 record, with 2 bits per power,
+// the amount that must be added to get exactly the value of the full table.
+func tables128() []byte {
+	b := bytes.NewBuffer(header)
+	mask64 := new(big.Int).Sub(new(big.Int).Lsh(big.NewInt(1), 64), big.NewInt(1))
+	words := func(v *big.Int, n int) []uint64 {
+		v = new(big.Int).Set(v)
+		w := make([]uint64, n)
+		for k := range w {
+			w[k] = new(big.Int).And(v, mask64).Uint64()
+			v.Rsh(v, 64)
+		}
+		if v.Sign() != 0 {
+			log.Fatalf("table entry does not fit into %d bits", 64*n)
+		}
+		return w
+	}
+	// split returns 5^i with pow5NumBits128 bits.
+	split := func(i int) *big.Int {
+		v := pow5(i)
+		rsh(v, v.BitLen()-pow5NumBits128)
+		return v
+	}
+	// invSplit returns floor(2^(floor(log_2 5^i) + pow5InvNumBits128) / 5^i).
+	invSplit := func(i int) *big.Int {
+		v := pow5(i)
 		inv := big.NewInt(1)
-		rsh(inv, -shift)
-		inv.Quo(inv, pow5)
-		inv.Add(inv, big.NewInt(1))
-		lo := new(big.Int).And(inv, mask64)
-		hi := new(big.Int).Rsh(inv, 64)
-		fmt.Fprintf(b, "{%d, %d},\n", lo.Uint64(), hi.Uint64())
+		rsh(inv, -(v.BitLen() - 1 + pow5InvNumBits128))
+		return inv.Quo(inv, v)
+	}
+
+	fmt.Fprintf(b, "const pow5TableSize128 = %d\n", pow5TableSize128)
+	fmt.Fprintln(b, "var pow5Table128 = [...]uint128{")
+	for i := 0; i < pow5TableSize128; i++ {
+		w := words(pow5(i), 2)
+		fmt.Fprintf(b, "{%d, %d},\n", w[0], w[1])
+	}
+	fmt.Fprintln(b, "}")
+
+	fmt.Fprintf(b, "const pow5NumBits128 = %d\n", pow5NumBits128)
+	fmt.Fprintln(b, "var pow5Split128 = [...][4]uint64{")
+	for base := 0; base*pow5TableSize128 < pow5Index128; base++ {
+		w := words(split(base*pow5TableSize128), 4)
+		fmt.Fprintf(b, "{%d, %d, %d, %d},\n", w[0], w[1], w[2], w[3])
+	}
+	fmt.Fprintln(b, "}")
+
+	fmt.Fprintf(b, "const pow5InvNumBits128 = %d\n", pow5InvNumBits128)
+	fmt.Fprintln(b, "var pow5InvSplit128 = [...][4]uint64{")
+	for base := 0; base*pow5TableSize128 < pow5Index128+pow5TableSize128; base++ {
+		w := words(invSplit(base*pow5TableSize128), 4)
+		fmt.Fprintf(b, "{%d, %d, %d, %d},\n", w[0], w[1], w[2], w[3])
+	}
+	fmt.Fprintln(b, "}")
+
+	// The run-time computation for 5^i, where i is not a multiple of
+	// pow5TableSize128, is
+	//
+	//   floor(5^offset * split(base) / 2^delta) + corr
+	//
+	// where base (rounded down for 5^i and up for 5^-i) and offset are
+	// such that 5^i = 5^(base*pow5TableSize128) * 5^offset, and delta is
+	// the difference of the bit lengths of the two powers.
+	// Exact multiples of pow5TableSize128 are looked up directly
+	// (adding 1 for the inverse, which is stored rounded down).
+	errors := func(name string, exact func(i int) *big.Int, approx func(i int) *big.Int) {
+		var packed []uint64
+		for i := 0; i < pow5Index128; i++ {
+			if i%32 == 0 {
+				packed = append(packed, 0)
+			}
+			if i%pow5TableSize128 == 0 {
+				continue
+			}
+			corr := new(big.Int).Sub(exact(i), approx(i))
+			if corr.Sign() < 0 || corr.Cmp(big.NewInt(3)) > 0 {
+				log.Fatalf("%s: correction for 5^%d out of range: %s", name, i, corr)
+			}
+			packed[i/32] |= corr.Uint64() << (2 * uint(i%32))
+		}
+		fmt.Fprintf(b, "var %s = [...]uint64{\n", name)
+		for i, w := range packed {
+			fmt.Fprintf(b, "%#016x,", w)
+			if i%4 == 3 {
+				fmt.Fprintln(b)
+			}
+		}
+		fmt.Fprintln(b, "\n}")
+	}
+	errors("pow5Errors128", split, func(i int) *big.Int {
+		base := i / pow5TableSize128
+		base2 := base * pow5TableSize128
+		delta := pow5(i).BitLen() - pow5(base2).BitLen()
+		v := new(big.Int).Mul(pow5(i-base2), split(base2))
+		return v.Rsh(v, uint(delta))
+	})
+	errors("pow5InvErrors128", func(i int) *big.Int {
+		return new(big.Int).Add(invSplit(i), big.NewInt(1))
+	}, func(i int) *big.Int {
+		base := (i + pow5TableSize128 - 1) / pow5TableSize128
+		base2 := base * pow5TableSize128
+		delta := pow5(base2).BitLen() - pow5(i).BitLen()
+		v := new(big.Int).Mul(pow5(base2-i), invSplit(base2))
+		return v.Rsh(v, uint(delta))
+	})
+
+	return b.Bytes()
+}
+
+// precTables generates the tables used by the fixed-precision algorithm.
+//
+// For the integer part of a number, pow10Split holds, for each index idx
+// (covering binary exponents up to 16*idx) and each 9-digit block i,
+// ceil(2^(16*idx+pow10AdditionalBits) / 10^(9*i)).
+//
+// For the fractional part, pow10Split2 holds, for each index idx (covering
+// binary exponents down to -16*idx-15) and each 9-digit block i that may be
+// nonzero, ceil(10^(9*(i+1)) * 2^additionalBits2 / 2^(16*idx)).
+//
+// Only the value of the multiplication modulo 10^9 is used after shifting
+// by at most 136 bits, so the entries are reduced modulo 10^9 * 2^136 to fit
+// into 192 bits.
+func precTables() []byte {
+	b := bytes.NewBuffer(header)
+	mod := new(big.Int).Lsh(big.NewInt(1e9), 136)
+	mask64 := new(big.Int).Sub(new(big.Int).Lsh(big.NewInt(1), 64), big.NewInt(1))
+	printSplit := func(v *big.Int) {
+		v.Mod(v, mod)
+		var w [3]uint64
+		for k := range w {
+			w[k] = new(big.Int).And(v, mask64).Uint64()
+			v.Rsh(v, 64)
+		}
+		if v.Sign() != 0 {
+			log.Fatal("table entry does not fit into 192 bits")
+		}
+		fmt.Fprintf(b, "{%d, %d, %d},\n", w[0], w[1], w[2])
+	}
+
+	fmt.Fprintf(b, "const pow10AdditionalBits = %d\n", pow10AdditionalBits)
+	var offsets []int
+	n := 0
+	fmt.Fprintln(b, "var pow10Split = [...][3]uint64{")
+	for idx := 0; idx <= (maxExp64+15)/16; idx++ {
+		offsets = append(offsets, n)
+		for i := 0; i < lengthForIndex(idx); i++ {
+			num := new(big.Int).Lsh(big.NewInt(1), uint(16*idx+pow10AdditionalBits))
+			printSplit(ceilDiv(num, pow10(9*i)))
+			n++
+		}
+	}
+	fmt.Fprintln(b, "}")
+	printOffsets(b, "pow10Offset", offsets)
+
+	fmt.Fprintf(b, "const additionalBits2 = %d\n", additionalBits2)
+	var minBlocks []int
+	offsets = offsets[:0]
+	n = 0
+	fmt.Fprintln(b, "var pow10Split2 = [...][3]uint64{")
+	for idx := 0; idx <= -minExp64/16; idx++ {
+		offsets = append(offsets, n)
+		// Every block before minBlock is zero because the number is less
+		// than 2^53 * 2^(-16*idx).
+		minBlock := 0
+		for {
+			v := new(big.Int).Lsh(pow10(9*(minBlock+1)), 53)
+			if v.Cmp(new(big.Int).Lsh(big.NewInt(1), uint(16*idx))) > 0 {
+				break
+			}
+			minBlock++
+		}
+		minBlocks = append(minBlocks, minBlock)
+		// Every block i with 9*i >= -e2 is zero because the fractional
+		// part of the number has at most -e2 digits.
+		maxBlock := (16*idx + 15 + 8) / 9
+		for i := minBlock; i < maxBlock; i++ {
+			num := new(big.Int).Lsh(pow10(9*(i+1)), additionalBits2)
+			den := new(big.Int).Lsh(big.NewInt(1), uint(16*idx))
+			printSplit(ceilDiv(num, den))
+			n++
+		}
+	}
+	offsets = append(offsets, n)
+	fmt.Fprintln(b, "}")
+	printOffsets(b, "pow10Offset2", offsets)
+
+	fmt.Fprintln(b, "var minBlock2 = [...]uint8{")
+	for i, m := range minBlocks {
+		fmt.Fprintf(b, "%d,", m)
+		if i%16 == 15 {
+			fmt.Fprintln(b)
+		}
 	}
 	fmt.Fprintln(b, "\n}")
+	return b.Bytes()
+}
+
+func printOffsets(b *bytes.Buffer, name string, offsets []int) {
+	fmt.Fprintf(b, "var %s = [...]uint16{\n", name)
+	for i, off := range offsets {
+		fmt.Fprintf(b, "%d,", off)
+		if i%16 == 15 {
+			fmt.Fprintln(b)
+		}
+	}
+	fmt.Fprintln(b, "\n}")
+}
+
+// lengthForIndex returns the number of 9-digit blocks needed for the integer
+// part of a number less than 2^53 * 2^(16*idx).
+// It must match the function of the same name in prec.go.
+func lengthForIndex(idx int) int {
+	// +1 for ceil, +16 for mantissa, +8 to round up when dividing by 9
+	log10Pow2 := (16 * idx * 78913) >> 18
+	return (log10Pow2 + 1 + 16 + 8) / 9
+}
+
+func pow10(n int) *big.Int {
+	return new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(n)), nil)
+}
+
+func ceilDiv(x, y *big.Int) *big.Int {
+	q, r := new(big.Int).QuoRem(x, y, new(big.Int))
+	if r.Sign() != 0 {
+		q.Add(q, big.NewInt(1))
+	}
+	return q
+}
 
-	text, err := format.Source(b.Bytes())
+func writeSource(filename string, src []byte) {
+	text, err := format.Source(src)
 	if err != nil {
 		log.Fatal(err)
 	}
-	if err := ioutil.WriteFile("tables.go", text, 0644); err != nil {
+	if err := ioutil.WriteFile(filename, text, 0644); err != nil {
 		log.Fatal(err)
 	}
 }
@@ -0,0 +1,243 @@
+// Copyright 2019 Caleb Spare
+//
+// The contents of this file may be used under the terms of the Apache License,
+// Version 2.0.
+//
+//    (See accompanying file LICENSE or copy at
+//     http://www.apache.org/licenses/LICENSE-2.0)
+//
+// Unless required by applicable law or agreed to in writing, this software
+// is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.
+
+package ryu
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+)
+
+// A NonFiniteMode specifies how Float64With and Float32With encode NaN and
+// the infinities as JSON, which has no representation for them. The modes are
+// the types NonFiniteError, NonFiniteNull, and NonFiniteString.
+type NonFiniteMode interface {
+	marshalNonFinite(err *UnsupportedValueError) ([]byte, error)
+}
+
+// NonFiniteError makes MarshalJSON return an *UnsupportedValueError for
+// non-finite numbers, as json.Marshal does for a float64.
+type NonFiniteError struct{}
+
+// NonFiniteNull makes MarshalJSON encode non-finite numbers as null.
+type NonFiniteNull struct{}
+
+// NonFiniteString makes MarshalJSON encode non-finite numbers as the strings
+// "NaN", "+Inf", and "-Inf".
+type NonFiniteString struct{}
+
+func (NonFiniteError) marshalNonFinite(err *UnsupportedValueError) ([]byte, error) {
+	return nil, err
+}
+
+func (NonFiniteNull) marshalNonFinite(err *UnsupportedValueError) ([]byte, error) {
+	return []byte("null"), nil
+}
+
+func (NonFiniteString) marshalNonFinite(err *UnsupportedValueError) ([]byte, error) {
+	return []byte(`"` + err.Str + `"`), nil
+}
+
+// Float64 is a float64 that is marshaled and unmarshaled using Ryu. Like
+// json.Marshal, its MarshalJSON method returns an error for NaN and the
+// infinities; use Float64With for other encodings of them.
+type Float64 = Float64With[NonFiniteError]
+
+// Float64With is a float64 that is marshaled and unmarshaled using Ryu.
+// It implements encoding.TextMarshaler, encoding.TextUnmarshaler,
+// json.Marshaler, json.Unmarshaler, and sql.Scanner, so it can be used in
+// place of float64 in types handled by encoding/json, encoding/xml, and
+// database/sql. (It does not implement driver.Valuer, which would make every
+// program using this package depend on database/sql/driver; database/sql
+// converts it to a float64 query argument anyway.) M selects the JSON
+// encoding of NaN and the infinities:
+//
+//	type Reading struct {
+//		Value ryu.Float64With[ryu.NonFiniteNull] // NaN is written as null
+//	}
+type Float64With[M NonFiniteMode] float64
+
+// MarshalText implements encoding.TextMarshaler. The text is the shortest
+// representation of f as written by AppendFloat64.
+func (f Float64With[M]) MarshalText() ([]byte, error) {
+	return AppendFloat64(make([]byte, 0, 24), float64(f)), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler by parsing text with
+// ParseFloat64.
+func (f *Float64With[M]) UnmarshalText(text []byte) error {
+	v, err := ParseFloat64(string(text))
+	if err != nil {
+		return err
+	}
+	*f = Float64With[M](v)
+	return nil
+}
+
+// MarshalJSON implements json.Marshaler. Finite numbers are written as by
+// AppendJSONFloat64, which is the same as json.Marshal for a float64. NaN and
+// the infinities are written according to M.
+func (f Float64With[M]) MarshalJSON() ([]byte, error) {
+	b, err := AppendJSONFloat64(make([]byte, 0, 24), float64(f))
+	if err != nil {
+		var m M
+		return m.marshalNonFinite(err.(*UnsupportedValueError))
+	}
+	return b, nil
+}
+
+// UnmarshalJSON implements json.Unmarshaler. It accepts a JSON number, the
+// strings "NaN", "+Inf", and "-Inf" (whatever M is), or null, which leaves f
+// unchanged.
+func (f *Float64With[M]) UnmarshalJSON(data []byte) error {
+	s, ok := jsonNumber(data)
+	if !ok {
+		return nil
+	}
+	v, err := ParseFloat64(s)
+	if err != nil {
+		return err
+	}
+	*f = Float64With[M](v)
+	return nil
+}
+
+// Scan implements sql.Scanner. It accepts float64 and int64 values as well
+// as strings and byte slices, which are parsed with ParseFloat64.
+func (f *Float64With[M]) Scan(src interface{}) error {
+	var v float64
+	switch src := src.(type) {
+	case float64:
+		v = src
+	case int64:
+		v = float64(src)
+	case []byte:
+		var err error
+		if v, err = ParseFloat64(string(src)); err != nil {
+			return scanError(src, "Float64", err)
+		}
+	case string:
+		var err error
+		if v, err = ParseFloat64(src); err != nil {
+			return scanError(src, "Float64", err)
+		}
+	default:
+		return scanError(src, "Float64", nil)
+	}
+	*f = Float64With[M](v)
+	return nil
+}
+
+// Float32 is the float32 counterpart of Float64.
+type Float32 = Float32With[NonFiniteError]
+
+// Float32With is the float32 counterpart of Float64With.
+type Float32With[M NonFiniteMode] float32
+
+// MarshalText implements encoding.TextMarshaler. The text is the shortest
+// representation of f as written by AppendFloat32.
+func (f Float32With[M]) MarshalText() ([]byte, error) {
+	return AppendFloat32(make([]byte, 0, 16), float32(f)), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler by parsing text with
+// ParseFloat32.
+func (f *Float32With[M]) UnmarshalText(text []byte) error {
+	v, err := ParseFloat32(string(text))
+	if err != nil {
+		return err
+	}
+	*f = Float32With[M](v)
+	return nil
+}
+
+// MarshalJSON implements json.Marshaler. Finite numbers are written as by
+// AppendJSONFloat32, which is the same as json.Marshal for a float32. NaN and
+// the infinities are written according to M.
+func (f Float32With[M]) MarshalJSON() ([]byte, error) {
+	b, err := AppendJSONFloat32(make([]byte, 0, 16), float32(f))
+	if err != nil {
+		var m M
+		return m.marshalNonFinite(err.(*UnsupportedValueError))
+	}
+	return b, nil
+}
+
+// UnmarshalJSON implements json.Unmarshaler. It accepts the same inputs as
+// Float64With.UnmarshalJSON.
+func (f *Float32With[M]) UnmarshalJSON(data []byte) error {
+	s, ok := jsonNumber(data)
+	if !ok {
+		return nil
+	}
+	v, err := ParseFloat32(s)
+	if err != nil {
+		return err
+	}
+	*f = Float32With[M](v)
+	return nil
+}
+
+// Scan implements sql.Scanner. It accepts the same values as Float64With.Scan.
+// A float64 value that overflows a float32 is an error.
+func (f *Float32With[M]) Scan(src interface{}) error {
+	var v float32
+	switch src := src.(type) {
+	case float64:
+		v = float32(src)
+		if math.IsInf(float64(v), 0) && !math.IsInf(src, 0) {
+			return scanError(src, "Float32", strconv.ErrRange)
+		}
+	case int64:
+		v = float32(src)
+	case []byte:
+		var err error
+		if v, err = ParseFloat32(string(src)); err != nil {
+			return scanError(src, "Float32", err)
+		}
+	case string:
+		var err error
+		if v, err = ParseFloat32(src); err != nil {
+			return scanError(src, "Float32", err)
+		}
+	default:
+		return scanError(src, "Float32", nil)
+	}
+	*f = Float32With[M](v)
+	return nil
+}
+
+// jsonNumber returns the number to parse from the JSON value data. It returns
+// ok = false for null. A string is returned unquoted if it is one of those
+// written by NonFiniteString; any other string is returned quoted so that
+// parsing it fails.
+func jsonNumber(data []byte) (s string, ok bool) {
+	s = string(data)
+	switch s {
+	case "null":
+		return "", false
+	case `"NaN"`, `"+Inf"`, `"-Inf"`:
+		return s[1 : len(s)-1], true
+	}
+	return s, true
+}
+
+func scanError(src interface{}, typ string, err error) error {
+	switch {
+	case err != nil:
+		return fmt.Errorf("ryu: converting %T to %s: %v", src, typ, err)
+	case src == nil:
+		return fmt.Errorf("ryu: converting NULL to %s is unsupported", typ)
+	}
+	return fmt.Errorf("ryu: unsupported Scan, storing %T into %s", src, typ)
+}
@@ -0,0 +1,271 @@
+// Copyright 2019 Caleb Spare
+//
+// The contents of this file may be used under the terms of the Apache License,
+// Version 2.0.
+//
+//    (See accompanying file LICENSE or copy at
+//     http://www.apache.org/licenses/LICENSE-2.0)
+//
+// Unless required by applicable law or agreed to in writing, this software
+// is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.
+
+package ryu
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"encoding"
+	"encoding/json"
+	"encoding/xml"
+	"math"
+	"os/exec"
+	"strings"
+	"testing"
+)
+
+var (
+	_ encoding.TextMarshaler   = Float64(0)
+	_ encoding.TextUnmarshaler = (*Float64)(nil)
+	_ json.Marshaler           = Float64(0)
+	_ json.Unmarshaler         = (*Float64)(nil)
+	_ sql.Scanner              = (*Float64)(nil)
+
+	_ encoding.TextMarshaler   = Float32(0)
+	_ encoding.TextUnmarshaler = (*Float32)(nil)
+	_ json.Marshaler           = Float32(0)
+	_ json.Unmarshaler         = (*Float32)(nil)
+	_ sql.Scanner              = (*Float32)(nil)
+
+	_ json.Marshaler   = Float64With[NonFiniteNull](0)
+	_ json.Unmarshaler = (*Float32With[NonFiniteString])(nil)
+)
+
+func TestFloat64JSON(t *testing.T) {
+	for _, f := range append(genericTestCases, float64TestCases...) {
+		if math.IsNaN(f) || math.IsInf(f, 0) {
+			continue
+		}
+		got, err := json.Marshal(struct{ X Float64 }{Float64(f)})
+		if err != nil {
+			t.Fatal(err)
+		}
+		want, err := json.Marshal(struct{ X float64 }{f})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(got) != string(want) {
+			t.Errorf("json.Marshal(%v): got %s; want %s", f, got, want)
+		}
+		var v struct{ X Float64 }
+		if err := json.Unmarshal(got, &v); err != nil {
+			t.Fatalf("json.Unmarshal(%s): %s", got, err)
+		}
+		if math.Float64bits(float64(v.X)) != math.Float64bits(f) {
+			t.Errorf("json.Unmarshal(%s): got %v; want %v", got, v.X, f)
+		}
+	}
+}
+
+func TestFloat32JSON(t *testing.T) {
+	for _, f64 := range genericTestCases {
+		f := float32(f64)
+		if math.IsNaN(float64(f)) || math.IsInf(float64(f), 0) {
+			continue
+		}
+		got, err := json.Marshal(struct{ X Float32 }{Float32(f)})
+		if err != nil {
+			t.Fatal(err)
+		}
+		want, err := json.Marshal(struct{ X float32 }{f})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(got) != string(want) {
+			t.Errorf("json.Marshal(%v): got %s; want %s", f, got, want)
+		}
+		var v struct{ X Float32 }
+		if err := json.Unmarshal(got, &v); err != nil {
+			t.Fatalf("json.Unmarshal(%s): %s", got, err)
+		}
+		if math.Float32bits(float32(v.X)) != math.Float32bits(f) {
+			t.Errorf("json.Unmarshal(%s): got %v; want %v", got, v.X, f)
+		}
+	}
+}
+
+func TestJSONNonFinite(t *testing.T) {
+	for _, f := range []float64{math.NaN(), math.Inf(1), math.Inf(-1)} {
+		for _, v := range []json.Marshaler{Float64(f), Float32(f)} {
+			if got, err := v.MarshalJSON(); err == nil {
+				t.Errorf("(%T(%v)).MarshalJSON(): got %s; want *UnsupportedValueError", v, f, got)
+			} else if _, ok := err.(*UnsupportedValueError); !ok {
+				t.Errorf("(%T(%v)).MarshalJSON(): got error %T; want *UnsupportedValueError", v, f, err)
+			}
+		}
+	}
+	for _, tt := range []struct {
+		v    json.Marshaler
+		want string
+	}{
+		{Float64With[NonFiniteNull](math.NaN()), "null"},
+		{Float32With[NonFiniteNull](math.Inf(-1)), "null"},
+		{Float64With[NonFiniteNull](1.5), "1.5"},
+		{Float64With[NonFiniteString](math.NaN()), `"NaN"`},
+		{Float64With[NonFiniteString](math.Inf(1)), `"+Inf"`},
+		{Float32With[NonFiniteString](math.Inf(-1)), `"-Inf"`},
+		{Float32With[NonFiniteString](1.5), "1.5"},
+	} {
+		got, err := tt.v.MarshalJSON()
+		if err != nil || string(got) != tt.want {
+			t.Errorf("(%T(%v)).MarshalJSON(): got (%s, %v); want %s", tt.v, tt.v, got, err, tt.want)
+		}
+	}
+
+	// Different modes can be used side by side.
+	in := struct {
+		A []Float64With[NonFiniteString]
+		B Float32With[NonFiniteNull]
+		C Float64
+	}{
+		A: []Float64With[NonFiniteString]{1, Float64With[NonFiniteString](math.Inf(1)),
+			Float64With[NonFiniteString](math.Inf(-1)), Float64With[NonFiniteString](math.NaN())},
+		B: Float32With[NonFiniteNull](math.NaN()),
+		C: 2,
+	}
+	b, err := json.Marshal(in)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := `{"A":[1,"+Inf","-Inf","NaN"],"B":null,"C":2}`; string(b) != want {
+		t.Fatalf("json.Marshal: got %s; want %s", b, want)
+	}
+	var out struct{ A []Float32 }
+	if err := json.Unmarshal(b, &out); err != nil {
+		t.Fatal(err)
+	}
+	a := out.A
+	if len(a) != 4 || a[0] != 1 || !math.IsInf(float64(a[1]), 1) ||
+		!math.IsInf(float64(a[2]), -1) || a[3] == a[3] {
+		t.Fatalf("json.Unmarshal(%s): got %v", b, a)
+	}
+}
+
+func TestUnmarshalJSON(t *testing.T) {
+	f := Float64(3)
+	if err := json.Unmarshal([]byte("null"), &f); err != nil || f != 3 {
+		t.Errorf("json.Unmarshal(null): got (%v, %v); want (3, <nil>)", f, err)
+	}
+	for _, s := range []string{`"1.5"`, `"Inf"`, `true`, `[1]`, `1e400`} {
+		if err := json.Unmarshal([]byte(s), &f); err == nil {
+			t.Errorf("json.Unmarshal(%s): got %v; want error", s, f)
+		}
+	}
+	var g Float32
+	if err := json.Unmarshal([]byte("1e39"), &g); err == nil {
+		t.Errorf("json.Unmarshal(1e39) into Float32: got %v; want error", g)
+	}
+}
+
+func TestFloatText(t *testing.T) {
+	type T struct {
+		A Float64 `xml:"a,attr"`
+		B Float32 `xml:"b"`
+	}
+	in := T{A: 1e23, B: 0.3}
+	b, err := xml.Marshal(in)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := `<T a="1e+23"><b>3e-01</b></T>`; string(b) != want {
+		t.Fatalf("xml.Marshal: got %s; want %s", b, want)
+	}
+	var out T
+	if err := xml.Unmarshal(b, &out); err != nil {
+		t.Fatal(err)
+	}
+	if out != in {
+		t.Fatalf("xml.Unmarshal(%s): got %+v; want %+v", b, out, in)
+	}
+
+	for _, f := range []float64{math.Inf(1), math.Inf(-1), math.NaN(), math.Copysign(0, -1)} {
+		text, err := Float64(f).MarshalText()
+		if err != nil {
+			t.Fatal(err)
+		}
+		var g Float64
+		if err := g.UnmarshalText(text); err != nil {
+			t.Fatal(err)
+		}
+		if math.Float64bits(float64(g)) != math.Float64bits(f) && !math.IsNaN(f) {
+			t.Errorf("UnmarshalText(%s): got %v; want %v", text, g, f)
+		}
+	}
+	var g Float64
+	if err := g.UnmarshalText([]byte("x")); err == nil {
+		t.Error("UnmarshalText(x): got nil error")
+	}
+}
+
+func TestFloatSQL(t *testing.T) {
+	for _, tt := range []struct {
+		src  interface{}
+		want float64
+		ok   bool
+	}{
+		{1.5, 1.5, true},
+		{int64(-3), -3, true},
+		{[]byte("2.5e-3"), 2.5e-3, true},
+		{"0.1", 0.1, true},
+		{"x", 0, false},
+		{true, 0, false},
+		{nil, 0, false},
+	} {
+		var f Float64
+		err := f.Scan(tt.src)
+		if (err == nil) != tt.ok || (tt.ok && float64(f) != tt.want) {
+			t.Errorf("Float64.Scan(%#v): got (%v, %v); want %v", tt.src, f, err, tt.want)
+		}
+		var g Float32
+		err = g.Scan(tt.src)
+		if (err == nil) != tt.ok || (tt.ok && g != Float32(tt.want)) {
+			t.Errorf("Float32.Scan(%#v): got (%v, %v); want %v", tt.src, g, err, float32(tt.want))
+		}
+	}
+	var g Float32
+	if err := g.Scan(1e300); err == nil {
+		t.Errorf("Float32.Scan(1e300): got %v; want error", g)
+	}
+
+	// database/sql converts query arguments of both types to float64.
+	for _, tt := range []struct {
+		v    interface{}
+		want float64
+	}{
+		{Float64(0.1), 0.1},
+		{Float32(0.1), float64(float32(0.1))},
+		{Float64With[NonFiniteNull](2), 2},
+	} {
+		v, err := driver.DefaultParameterConverter.ConvertValue(tt.v)
+		if err != nil || v != tt.want {
+			t.Errorf("ConvertValue(%T(%v)): got (%#v, %v); want %v", tt.v, tt.v, v, err, tt.want)
+		}
+	}
+}
+
+func TestNoDriverDependency(t *testing.T) {
+	// Implementing driver.Valuer would pull database/sql/driver, and with it
+	// context, math/big, and more, into every binary that uses ryu.
+	if _, err := exec.LookPath("go"); err != nil {
+		t.Skip("go command not found")
+	}
+	out, err := exec.Command("go", "list", "-deps", ".").Output()
+	if err != nil {
+		t.Fatalf("go list: %v", err)
+	}
+	for _, pkg := range strings.Fields(string(out)) {
+		if pkg == "database/sql/driver" {
+			t.Fatal("package ryu depends on database/sql/driver")
+		}
+	}
+}
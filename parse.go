@@ -0,0 +1,276 @@
+// Copyright 2019 Ulf Adams
+// Modifications copyright 2019 Caleb Spare
+//
+// The contents of this file may be used under the terms of the Apache License,
+// Version 2.0.
+//
+//    (See accompanying file LICENSE or copy at
+//     http://www.apache.org/licenses/LICENSE-2.0)
+//
+// Unless required by applicable law or agreed to in writing, this software
+// is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.
+//
+// The code in this file is part of a Go translation of the C code written by
+// Ulf Adams which may be found at https://github.com/ulfjack/ryu. That source
+// code is licensed under Apache 2.0 and this code is derivative work thereof.
+
+package ryu
+
+import (
+	"math"
+	"math/bits"
+	"strconv"
+)
+
+// This file implements parsing of decimal strings (s2d.c in the C library).
+
+// ParseFloat64 converts the string s to a 64-bit floating point number.
+// It behaves like strconv.ParseFloat(s, 64), including the errors it returns,
+// which have type *strconv.NumError.
+//
+// Decimal numbers with at most 17 significant digits and an exponent of at
+// most 3 digits are converted using the Ryu tables. Other inputs, such as
+// longer numbers, numbers with more than 291 decimal places, hexadecimal
+// floating-point numbers, infinities, NaNs, and malformed strings, are
+// handled by strconv.ParseFloat.
+func ParseFloat64(s string) (float64, error) {
+	neg, m10, m10digits, e10, ok := readDecimal(s)
+	// The inverse table is sized for formatting, which needs 5^-e10 only
+	// up to 5^291; m10 * 10^e10 may need up to 5^340.
+	if !ok || -e10 >= negTableSize64 {
+		return strconv.ParseFloat(s, 64)
+	}
+	u, ok := decimalToFloatBits(m10, m10digits, e10, mantBits64, expBits64, bias64)
+	u |= boolToUint64(neg) << (mantBits64 + expBits64)
+	f := math.Float64frombits(u)
+	if !ok {
+		return f, rangeError(s)
+	}
+	return f, nil
+}
+
+// ParseFloat32 converts the string s to a 32-bit floating point number.
+// It behaves like strconv.ParseFloat(s, 32), including the errors it returns,
+// which have type *strconv.NumError.
+//
+// See ParseFloat64 for which inputs are handled by strconv.ParseFloat.
+func ParseFloat32(s string) (float32, error) {
+	neg, m10, m10digits, e10, ok := readDecimal(s)
+	if !ok {
+		f, err := strconv.ParseFloat(s, 32)
+		return float32(f), err
+	}
+	u, ok := decimalToFloatBits(m10, m10digits, e10, mantBits32, expBits32, bias32)
+	u |= boolToUint64(neg) << (mantBits32 + expBits32)
+	f := math.Float32frombits(uint32(u))
+	if !ok {
+		return f, rangeError(s)
+	}
+	return f, nil
+}
+
+func rangeError(s string) error {
+	return &strconv.NumError{Func: "ParseFloat", Num: s, Err: strconv.ErrRange}
+}
+
+// readDecimal parses s as [+-]digits[.digits][(e|E)[+-]digits], returning
+// the sign and m10 * 10^e10 along with the number of significant digits of
+// m10. It reports false if s does not have this form or if it has more digits
+// than can be handled by decimalToFloatBits.
+func readDecimal(s string) (neg bool, m10 uint64, m10digits, e10 int32, ok bool) {
+	i := 0
+	if i < len(s) && (s[i] == '+' || s[i] == '-') {
+		neg = s[i] == '-'
+		i++
+	}
+	sawDigits := false
+	sawDot := false
+	var fracDigits int32
+	for ; i < len(s); i++ {
+		c := s[i]
+		if c == '.' {
+			if sawDot {
+				return false, 0, 0, 0, false
+			}
+			sawDot = true
+			continue
+		}
+		if c < '0' || c > '9' {
+			break
+		}
+		sawDigits = true
+		if m10digits >= 17 {
+			return false, 0, 0, 0, false
+		}
+		m10 = 10*m10 + uint64(c-'0')
+		if m10 != 0 {
+			m10digits++
+		}
+		if sawDot {
+			fracDigits++
+		}
+	}
+	if !sawDigits {
+		return false, 0, 0, 0, false
+	}
+	if i < len(s) && (s[i] == 'e' || s[i] == 'E') {
+		i++
+		negExp := false
+		if i < len(s) && (s[i] == '+' || s[i] == '-') {
+			negExp = s[i] == '-'
+			i++
+		}
+		if i == len(s) {
+			return false, 0, 0, 0, false
+		}
+		var e10digits int32
+		for ; i < len(s); i++ {
+			c := s[i]
+			if c < '0' || c > '9' {
+				return false, 0, 0, 0, false
+			}
+			if e10digits >= 3 {
+				return false, 0, 0, 0, false
+			}
+			e10 = 10*e10 + int32(c-'0')
+			if e10 != 0 {
+				e10digits++
+			}
+		}
+		if negExp {
+			e10 = -e10
+		}
+	}
+	if i < len(s) {
+		return false, 0, 0, 0, false
+	}
+	return neg, m10, m10digits, e10 - fracDigits, true
+}
+
+// decimalToFloatBits converts m10 * 10^e10, where m10 has m10digits
+// significant digits (at most 17), to the nearest floating-point number with
+// the given layout. It returns the bits of the result (without a sign) and
+// reports false if the result overflowed to infinity.
+func decimalToFloatBits(m10 uint64, m10digits, e10 int32, mantBits, expBits uint, bias int32) (uint64, bool) {
+	infBits := (uint64(1)<<expBits - 1) << mantBits
+	// The smallest power of 10 that is less than half the smallest
+	// subnormal number and the smallest power of 10 that is larger than
+	// the largest finite number, respectively.
+	minE10, maxE10 := int32(-324), int32(310)
+	if mantBits == mantBits32 {
+		minE10, maxE10 = -46, 40
+	}
+	if m10 == 0 || m10digits+e10 <= minE10 {
+		return 0, true
+	}
+	if m10digits+e10 >= maxE10 {
+		return infBits, false
+	}
+
+	// Convert to binary float m2 * 2^e2, while retaining information about
+	// whether the conversion was exact (trailingZeros). The float64 tables
+	// are precise enough that m2 is the exact floor of the scaled value
+	// for every m10 below 10^17: as in the Ryu paper's bounds for
+	// formatting, the smallest distance from m10 * 5^e10 / 2^j to the next
+	// integer over all such m10 exceeds the error of the table entry.
+	var (
+		e2            int32
+		m2            uint64
+		trailingZeros bool
+	)
+	if e10 >= 0 {
+		// The length of m * 10^e in bits is:
+		//   log2(m10 * 10^e10) = log2(m10) + e10 log2(10) = log2(m10) + e10 + e10 * log2(5)
+		//
+		// We want to compute the 53 top-most bits of a float64 (+1 for the
+		// implicit leading one in IEEE format). We therefore choose a
+		// binary output exponent of
+		//   log2(m10 * 10^e10) - 53.
+		//
+		// We use floor(log2(5^e10)) so that we get at least this many
+		// bits; better to have an additional bit than to not have enough
+		// bits.
+		e2 = floorLog2(m10) + e10 + pow5Bits(e10) - 1 - (mantBits64 + 1)
+
+		// We now compute [m10 * 10^e10 / 2^e2] = [m10 * 5^e10 / 2^(e2-e10)].
+		// To that end, we use pow5Mul64.
+		j := e2 - e10 - pow5Bits(e10) + pow5NumBits64
+		assert(j >= 0, "j >= 0")
+		m2 = mulShift64(m10, pow5Mul64(uint32(e10)), j)
+
+		// We also compute if the result is exact, i.e.,
+		//   [m10 * 10^e10 / 2^e2] == m10 * 10^e10 / 2^e2.
+		// This can only be the case if 2^e2 divides m10 * 10^e10, which
+		// in turn requires that the largest power of 2 that divides
+		// m10 + e10 is greater than e2. If e2 is less than e10, then the
+		// result must be exact. Otherwise we use the existing
+		// multipleOfPowerOfTwo64 function.
+		trailingZeros = e2 < e10 || (e2-e10 < 64 && multipleOfPowerOfTwo64(m10, uint32(e2-e10)))
+	} else {
+		e2 = floorLog2(m10) + e10 - pow5Bits(-e10) - (mantBits64 + 1)
+		j := e2 - e10 + pow5Bits(-e10) - 1 + pow5InvNumBits64
+		m2 = mulShift64(m10, pow5InvMul64(uint32(-e10)), j)
+
+		// We also compute if the result is exact, i.e.,
+		//   [m10 / (5^-e10 2^(e2-e10))] == m10 / (5^-e10 2^(e2-e10))
+		//
+		// If e2-e10 >= 0, we need to check whether (5^-e10 2^(e2-e10))
+		// divides m10, which is the case iff pow5(m10) >= -e10 AND
+		// pow2(m10) >= e2-e10.
+		//
+		// If e2-e10 < 0, we have actually computed
+		// [m10 * 2^(e10 e2) / 5^-e10] above, and we need to check whether
+		// 5^-e10 divides (m10 * 2^(e10-e2)), which is the case iff
+		// pow5(m10 * 2^(e10-e2)) = pow5(m10) >= -e10.
+		trailingZeros = (e2 < e10 || (e2-e10 < 64 && multipleOfPowerOfTwo64(m10, uint32(e2-e10)))) &&
+			multipleOfPowerOfFive64(m10, uint32(-e10))
+	}
+
+	// Compute the final IEEE exponent.
+	ieeeE2 := e2 + bias + floorLog2(m2)
+	if ieeeE2 < 0 {
+		ieeeE2 = 0
+	}
+	if ieeeE2 >= 1<<expBits-1 {
+		// The final IEEE exponent is larger than the maximum
+		// representable; return infinity.
+		return infBits, false
+	}
+
+	// We need to figure out how much we need to shift m2. The tricky part
+	// is that we need to take the final IEEE exponent into account, so we
+	// need to reverse the bias and also special-case the value 0.
+	shift := ieeeE2 - e2 - bias - int32(mantBits)
+	if ieeeE2 == 0 {
+		shift++
+	}
+	assert(shift > 0 && shift < 64, "0 < shift < 64")
+
+	// We need to round up if the exact value is more than 0.5 above the
+	// value we computed. That's equivalent to checking if the last removed
+	// bit was 1 and either the value was not just trailing zeros or the
+	// result would otherwise be odd.
+	//
+	// We need to update trailingZeros given that we have the exact output
+	// exponent ieeeE2 now.
+	trailingZeros = trailingZeros && m2&(uint64(1)<<uint(shift-1)-1) == 0
+	lastRemovedBit := (m2 >> uint(shift-1)) & 1
+	roundUp := lastRemovedBit != 0 && (!trailingZeros || (m2>>uint(shift))&1 != 0)
+
+	ieeeM2 := m2>>uint(shift) + boolToUint64(roundUp)
+	assert(ieeeM2 <= uint64(1)<<(mantBits+1), "ieeeM2 <= 1<<(mantBits+1)")
+	ieeeM2 &= uint64(1)<<mantBits - 1
+	if ieeeM2 == 0 && roundUp {
+		// Due to how IEEE represents infinity, we don't need to check
+		// for overflow here other than to report it.
+		ieeeE2++
+	}
+	u := uint64(ieeeE2)<<mantBits | ieeeM2
+	return u, u != infBits
+}
+
+// floorLog2 returns floor(log_2(v)) for v > 0.
+func floorLog2(v uint64) int32 {
+	return int32(63 - bits.LeadingZeros64(v))
+}
@@ -0,0 +1,223 @@
+// Copyright 2019 Caleb Spare
+//
+// The contents of this file may be used under the terms of the Apache License,
+// Version 2.0.
+//
+//    (See accompanying file LICENSE or copy at
+//     http://www.apache.org/licenses/LICENSE-2.0)
+//
+// Unless required by applicable law or agreed to in writing, this software
+// is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.
+
+package ryu
+
+import (
+	"fmt"
+	"math"
+	"math/big"
+	"math/rand"
+	"strconv"
+	"testing"
+)
+
+var parseTestCases = []string{
+	"0",
+	"-0",
+	"+0",
+	"0.000",
+	"1",
+	"+1",
+	"-1",
+	"1.",
+	".5",
+	"-.5",
+	"123.456",
+	"1e10",
+	"1E10",
+	"1e+10",
+	"1e-10",
+	"1.5e0003",
+	"0.000000000000000000000000000000001",
+	"00000000000000000000000000000001",
+	"12345678901234567",
+	"123456789012345678",
+	"9007199254740993",
+	"1.7976931348623157e308",
+	"1.7976931348623158e308",
+	"1.7976931348623159e308",
+	"1e308",
+	"1e309",
+	"-1e309",
+	"1e400",
+	"2.2250738585072011e-308",
+	"2.2250738585072012e-308",
+	"4.9406564584124654e-324",
+	"2.4703282292062327e-324",
+	"2.4703282292062328e-324",
+	"1e-324",
+	"1e-400",
+	"1.2345678901234567e-275",
+	"1.2345678901234567e-276",
+	"3.4028234e38",
+	"3.4028236e38",
+	"1.4e-45",
+	"7e-46",
+	"8e-46",
+	"1e-46",
+	"1e9999",
+	"0x1p-2",
+	"inf",
+	"-Infinity",
+	"NaN",
+	"1_000",
+	"",
+	".",
+	"-",
+	"e5",
+	"1e",
+	"1e+",
+	"1.2.3",
+	"1x",
+	"--1",
+}
+
+func TestParseFloat64(t *testing.T) {
+	for _, s := range parseTestCases {
+		testParseFloat64(t, s)
+	}
+}
+
+func TestParseFloat32(t *testing.T) {
+	for _, s := range parseTestCases {
+		testParseFloat32(t, s)
+	}
+}
+
+func testParseFloat64(t *testing.T, s string) {
+	t.Helper()
+	got, err := ParseFloat64(s)
+	want, wantErr := strconv.ParseFloat(s, 64)
+	if math.Float64bits(got) != math.Float64bits(want) && !(math.IsNaN(got) && math.IsNaN(want)) {
+		t.Errorf("ParseFloat64(%q): got %g; want %g", s, got, want)
+	}
+	if fmt.Sprint(err) != fmt.Sprint(wantErr) {
+		t.Errorf("ParseFloat64(%q): got error %v; want %v", s, err, wantErr)
+	}
+}
+
+func testParseFloat32(t *testing.T, s string) {
+	t.Helper()
+	got, err := ParseFloat32(s)
+	want64, wantErr := strconv.ParseFloat(s, 32)
+	want := float32(want64)
+	if math.Float32bits(got) != math.Float32bits(want) && !(got != got && want != want) {
+		t.Errorf("ParseFloat32(%q): got %g; want %g", s, got, want)
+	}
+	if fmt.Sprint(err) != fmt.Sprint(wantErr) {
+		t.Errorf("ParseFloat32(%q): got error %v; want %v", s, err, wantErr)
+	}
+}
+
+func TestParseFloatRoundTrip(t *testing.T) {
+	for i := 0; i < 1e5; i++ {
+		f := math.Float64frombits(rand.Uint64())
+		if math.IsNaN(f) || math.IsInf(f, 0) {
+			continue
+		}
+		s := FormatFloat64(f)
+		got, err := ParseFloat64(s)
+		if err != nil || got != f {
+			t.Fatalf("ParseFloat64(%q): got %g, %v; want %g", s, got, err, f)
+		}
+
+		f32 := math.Float32frombits(rand.Uint32())
+		if f32 != f32 || math.IsInf(float64(f32), 0) {
+			continue
+		}
+		s = FormatFloat32(f32)
+		got32, err := ParseFloat32(s)
+		if err != nil || got32 != f32 {
+			t.Fatalf("ParseFloat32(%q): got %g, %v; want %g", s, got32, err, f32)
+		}
+	}
+}
+
+// TestParseFloatNearHalfway checks 17-digit inputs just below and above the
+// exact midpoint between two adjacent floating-point numbers, which are the
+// hardest inputs to round correctly.
+func TestParseFloatNearHalfway(t *testing.T) {
+	for i := 0; i < 1e4; i++ {
+		f := math.Abs(math.Float64frombits(rand.Uint64()))
+		next := math.Nextafter(f, math.Inf(1))
+		if math.IsNaN(f) || math.IsInf(next, 0) {
+			continue
+		}
+		mid := new(big.Float).SetPrec(2000).SetFloat64(f)
+		mid.Add(mid, new(big.Float).SetFloat64(next))
+		mid.Quo(mid, big.NewFloat(2))
+		for _, mode := range []big.RoundingMode{big.ToZero, big.AwayFromZero} {
+			testParseFloat64(t, roundDecimal(mid, 17, mode))
+		}
+
+		f32 := float32(f)
+		next32 := math.Nextafter32(f32, float32(math.Inf(1)))
+		if math.IsInf(float64(next32), 0) {
+			continue
+		}
+		mid.SetFloat64(float64(f32))
+		mid.Add(mid, new(big.Float).SetFloat64(float64(next32)))
+		mid.Quo(mid, big.NewFloat(2))
+		for _, mode := range []big.RoundingMode{big.ToZero, big.AwayFromZero} {
+			testParseFloat32(t, roundDecimal(mid, 17, mode))
+		}
+	}
+}
+
+// roundDecimal formats x with n significant decimal digits, rounding in the
+// direction given by mode (big.ToZero or big.AwayFromZero).
+func roundDecimal(x *big.Float, n int, mode big.RoundingMode) string {
+	s := x.Text('e', n+40)
+	mant, exp := s[:n+1], s[n+2+40:]
+	digits := []byte(mant[:1] + mant[2:])
+	if mode == big.AwayFromZero {
+		for i := len(digits) - 1; i >= 0; i-- {
+			if digits[i] < '9' {
+				digits[i]++
+				break
+			}
+			digits[i] = '0'
+			if i == 0 {
+				// The number was all 9s; there's room for another
+				// digit at the front since we just need any string
+				// above x.
+				digits = append([]byte{'1'}, digits...)
+				e, _ := strconv.Atoi(exp[1:])
+				return fmt.Sprintf("%s.%se%d", digits[:1], digits[1:], e+1)
+			}
+		}
+	}
+	return string(digits[:1]) + "." + string(digits[1:]) + exp
+}
+
+func BenchmarkParseFloat64(b *testing.B) {
+	for _, f := range append(benchCases, benchCases64...) {
+		s := FormatFloat64(f)
+		b.Run(s, func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				ParseFloat64(s)
+			}
+		})
+	}
+}
+
+func BenchmarkStrconvParseFloat64(b *testing.B) {
+	for _, f := range append(benchCases, benchCases64...) {
+		s := FormatFloat64(f)
+		b.Run(s, func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				strconv.ParseFloat(s, 64)
+			}
+		})
+	}
+}
@@ -0,0 +1,31 @@
+// Copyright 2018 Ulf Adams
+// Modifications copyright 2019 Caleb Spare
+//
+// The contents of this file may be used under the terms of the Apache License,
+// Version 2.0.
+//
+//    (See accompanying file LICENSE or copy at
+//     http://www.apache.org/licenses/LICENSE-2.0)
+//
+// Unless required by applicable law or agreed to in writing, this software
+// is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.
+//
+// The code in this file is part of a Go translation of the C code written by
+// Ulf Adams which may be found at https://github.com/ulfjack/ryu. That source
+// code is licensed under Apache 2.0 and this code is derivative work thereof.
+
+//go:build !ryu_small
+
+package ryu
+
+// pow5Mul64 returns 5^i with pow5NumBits64 bits (rounded down).
+func pow5Mul64(i uint32) uint128 {
+	return pow5Split64[i]
+}
+
+// pow5InvMul64 returns 2^(floor(log_2(5^i))+pow5InvNumBits64) / 5^i
+// (rounded up).
+func pow5InvMul64(i uint32) uint128 {
+	return pow5InvSplit64[i]
+}
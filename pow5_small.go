@@ -0,0 +1,79 @@
+// Copyright 2018 Ulf Adams
+// Modifications copyright 2019 Caleb Spare
+//
+// The contents of this file may be used under the terms of the Apache License,
+// Version 2.0.
+//
+//    (See accompanying file LICENSE or copy at
+//     http://www.apache.org/licenses/LICENSE-2.0)
+//
+// Unless required by applicable law or agreed to in writing, this software
+// is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.
+//
+// The code in this file is part of a Go translation of the C code written by
+// Ulf Adams which may be found at https://github.com/ulfjack/ryu. That source
+// code is licensed under Apache 2.0 and this code is derivative work thereof.
+
+//go:build ryu_small
+
+package ryu
+
+import (
+	"math/bits"
+)
+
+// This file implements the size optimization of the C library
+// (RYU_OPTIMIZE_SIZE, d2s_small_table.h), selected with the ryu_small build
+// tag. Instead of the full float64 tables, which take about 10 kB, only
+// every pow5TableSize64th entry is stored and the rest are computed as
+// needed, which makes formatting and parsing float64s a little slower.
+
+// pow5Mul64 returns 5^i with pow5NumBits64 bits (rounded down).
+func pow5Mul64(i uint32) uint128 {
+	base := i / pow5TableSize64
+	base2 := base * pow5TableSize64
+	mul := pow5Split64Small[base]
+	if i == base2 {
+		return mul
+	}
+	offset := i - base2
+	delta := pow5Bits(int32(i)) - pow5Bits(int32(base2))
+	corr := (pow5Offsets64[i/16] >> (2 * (i % 16))) & 3
+	return mulPow5Shift64(pow5Table64[offset], mul, delta, uint64(corr))
+}
+
+// pow5InvMul64 returns 2^(floor(log_2(5^i))+pow5InvNumBits64) / 5^i
+// (rounded up).
+func pow5InvMul64(i uint32) uint128 {
+	base := (i + pow5TableSize64 - 1) / pow5TableSize64
+	base2 := base * pow5TableSize64
+	mul := pow5InvSplit64Small[base]
+	if i == base2 {
+		// The table entries are rounded down.
+		lo, c := bits.Add64(mul.lo, 1, 0)
+		return uint128{lo: lo, hi: mul.hi + c}
+	}
+	offset := base2 - i
+	delta := pow5Bits(int32(base2)) - pow5Bits(int32(i))
+	corr := (pow5InvOffsets64[i/16] >> (2 * (i % 16))) & 3
+	return mulPow5Shift64(pow5Table64[offset], mul, delta, uint64(corr))
+}
+
+// mulPow5Shift64 returns (m * mul) >> shift, plus corr.
+// The result must fit into 128 bits.
+func mulPow5Shift64(m uint64, mul uint128, shift int32, corr uint64) uint128 {
+	assert(shift > 0 && shift < 64, "0 < shift < 64")
+	// Compute the 192-bit product as b0 + b2<<64.
+	b0hi, b0lo := bits.Mul64(m, mul.lo)
+	b2hi, b2lo := bits.Mul64(m, mul.hi)
+	mid, c := bits.Add64(b0hi, b2lo, 0)
+	hi := b2hi + c
+	r := uint128{
+		lo: shiftRight128(uint128{lo: b0lo, hi: mid}, shift),
+		hi: shiftRight128(uint128{lo: mid, hi: hi}, shift),
+	}
+	r.lo, c = bits.Add64(r.lo, corr, 0)
+	r.hi += c
+	return r
+}
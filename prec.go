@@ -0,0 +1,611 @@
+// Copyright 2018 Ulf Adams
+// Modifications copyright 2019 Caleb Spare
+//
+// The contents of this file may be used under the terms of the Apache License,
+// Version 2.0.
+//
+//    (See accompanying file LICENSE or copy at
+//     http://www.apache.org/licenses/LICENSE-2.0)
+//
+// Unless required by applicable law or agreed to in writing, this software
+// is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.
+//
+// The code in this file is part of a Go translation of the C code written by
+// Ulf Adams which may be found at https://github.com/ulfjack/ryu. That source
+// code is licensed under Apache 2.0 and this code is derivative work thereof.
+
+package ryu
+
+import (
+	"math"
+	"math/bits"
+)
+
+// This file implements "Ryu printf", which prints a float64 with a given
+// number of digits (d2fixed.c in the C library).
+
+// FormatFloat64Prec converts the 64-bit floating point number f to a string
+// according to the format fmt and precision prec.
+// It behaves like strconv.FormatFloat(f, fmt, prec, 64) for the formats
+// 'e', 'E', 'f', 'g', and 'G'.
+func FormatFloat64Prec(f float64, fmt byte, prec int) string {
+	n := 24
+	if prec > 0 {
+		n += prec
+	}
+	return string(AppendFloat64Prec(make([]byte, 0, n), f, fmt, prec))
+}
+
+// AppendFloat64Prec appends the string form of the 64-bit floating point
+// number f, as generated by FormatFloat64Prec, to b and returns the extended
+// buffer.
+//
+// The precision prec controls the number of digits (excluding the exponent)
+// printed by the 'e', 'E', 'f', 'g', and 'G' formats, as in strconv. The
+// special precision -1 uses the smallest number of digits necessary such that
+// the result parses back to f, as computed by AppendFloat64 and friends.
+// For any other precision, the digits are computed exactly without falling
+// back to arbitrary-precision arithmetic.
+//
+// Any other format is printed as '%' followed by the format character.
+func AppendFloat64Prec(b []byte, f float64, fmt byte, prec int) []byte {
+	if prec < 0 {
+		return appendFloat64Shortest(b, f, fmt)
+	}
+
+	u := math.Float64bits(f)
+	neg := u>>(mantBits64+expBits64) != 0
+	mant := u & (uint64(1)<<mantBits64 - 1)
+	exp := (u >> mantBits64) & (uint64(1)<<expBits64 - 1)
+
+	if exp == uint64(1)<<expBits64-1 {
+		return appendSpecial(b, neg, false, mant == 0)
+	}
+
+	var e2 int32
+	var m2 uint64
+	if exp == 0 {
+		e2 = 1 - bias64 - mantBits64
+		m2 = mant
+	} else {
+		e2 = int32(exp) - bias64 - mantBits64
+		m2 = uint64(1)<<mantBits64 | mant
+	}
+
+	switch fmt {
+	case 'e', 'E':
+		return appendExpPrec64(b, neg, m2, e2, prec, fmt)
+	case 'f':
+		if neg {
+			b = append(b, '-')
+		}
+		if m2 == 0 {
+			b = append(b, '0')
+			if prec > 0 {
+				b = append(b, '.')
+				b = appendZeros(b, prec)
+			}
+			return b
+		}
+		return appendFixedPrec64(b, m2, e2, prec)
+	case 'g', 'G':
+		return appendGeneralPrec64(b, neg, m2, e2, prec, fmt)
+	}
+	return append(b, '%', fmt)
+}
+
+// appendFloat64Shortest appends the shortest representation of f in the
+// format fmt ('e', 'E', 'f', 'g', or 'G') to b.
+func appendFloat64Shortest(b []byte, f float64, fmt byte) []byte {
+	n := len(b)
+	switch fmt {
+	case 'e':
+		return AppendFloat64(b, f)
+	case 'E':
+		return upperExp(AppendFloat64(b, f), n)
+	case 'f':
+		return AppendFloat64Fixed(b, f)
+	case 'g':
+		return AppendFloat64General(b, f)
+	case 'G':
+		return upperExp(AppendFloat64General(b, f), n)
+	}
+	return append(b, '%', fmt)
+}
+
+// appendFloat32Shortest is like appendFloat64Shortest for float32s.
+func appendFloat32Shortest(b []byte, f float32, fmt byte) []byte {
+	n := len(b)
+	switch fmt {
+	case 'e':
+		return AppendFloat32(b, f)
+	case 'E':
+		return upperExp(AppendFloat32(b, f), n)
+	case 'f':
+		return AppendFloat32Fixed(b, f)
+	case 'g':
+		return AppendFloat32General(b, f)
+	case 'G':
+		return upperExp(AppendFloat32General(b, f), n)
+	}
+	return append(b, '%', fmt)
+}
+
+// upperExp replaces the exponent character written to b[n:] by
+// AppendFloat64, AppendFloat64General, or their float32 equivalents with 'E'.
+func upperExp(b []byte, n int) []byte {
+	for i := len(b) - 1; i > n; i-- {
+		if b[i] == 'e' {
+			b[i] = 'E'
+			break
+		}
+	}
+	return b
+}
+
+// appendExpPrec64 appends m2 * 2^e2 in exponent notation with prec digits
+// after the decimal point (d2exp_buffered_n).
+func appendExpPrec64(b []byte, neg bool, m2 uint64, e2 int32, prec int, fmt byte) []byte {
+	if neg {
+		b = append(b, '-')
+	}
+	n := len(b)
+	var exp int32
+	if m2 == 0 {
+		b = appendZeros(b, prec+1)
+	} else {
+		b, exp = appendExpDigits64(b, m2, e2, prec)
+	}
+	if prec > 0 {
+		// Move everything after the first digit to make room for the '.'.
+		b = append(b, 0)
+		copy(b[n+2:], b[n+1:])
+		b[n+1] = '.'
+	}
+
+	b = append(b, fmt)
+	if exp < 0 {
+		b = append(b, '-')
+		exp = -exp
+	} else {
+		b = append(b, '+')
+	}
+	if exp >= 100 {
+		b = append(b, '0'+byte(exp/100))
+		exp %= 100
+	}
+	return append(b, '0'+byte(exp/10), '0'+byte(exp%10))
+}
+
+// appendGeneralPrec64 appends m2 * 2^e2 using prec significant digits
+// according to the rules of the 'g' format.
+func appendGeneralPrec64(b []byte, neg bool, m2 uint64, e2 int32, prec int, fmt byte) []byte {
+	if prec == 0 {
+		prec = 1
+	}
+
+	// Compute the digits and then trim trailing zeros, as strconv does.
+	var buf [32]byte
+	var digits []byte
+	var exp int32
+	if m2 != 0 {
+		s := buf[:0]
+		if prec > len(buf) {
+			s = make([]byte, 0, prec)
+		}
+		digits, exp = appendExpDigits64(s, m2, e2, prec-1)
+		for len(digits) > 0 && digits[len(digits)-1] == '0' {
+			digits = digits[:len(digits)-1]
+		}
+	}
+	nd := len(digits)
+	dp := int(exp) + 1
+	if nd == 0 {
+		dp = 0
+	}
+
+	eprec := prec
+	if eprec > nd && nd >= dp {
+		eprec = nd
+	}
+	if x := dp - 1; x < -4 || x >= eprec {
+		if prec > nd {
+			prec = nd
+		}
+		return appendDigitsExp(b, neg, digits, exp, prec-1, fmt+'e'-'g')
+	}
+	if prec > dp {
+		prec = nd
+	}
+	prec -= dp
+	if prec < 0 {
+		prec = 0
+	}
+	return appendDigitsFixed(b, neg, digits, dp, prec)
+}
+
+// appendDigitsExp appends the decimal number 0.digits * 10^(exp+1) in
+// exponent notation with prec digits after the decimal point,
+// padding digits with zeros as needed.
+func appendDigitsExp(b []byte, neg bool, digits []byte, exp int32, prec int, fmt byte) []byte {
+	if neg {
+		b = append(b, '-')
+	}
+	if len(digits) == 0 {
+		b = append(b, '0')
+	} else {
+		b = append(b, digits[0])
+	}
+	if prec > 0 {
+		b = append(b, '.')
+		if len(digits) > 1 {
+			b = append(b, digits[1:]...)
+		}
+		if n := len(digits); n < prec+1 {
+			b = appendZeros(b, prec+1-n)
+		}
+	}
+
+	b = append(b, fmt)
+	if exp < 0 {
+		b = append(b, '-')
+		exp = -exp
+	} else {
+		b = append(b, '+')
+	}
+	if exp >= 100 {
+		b = append(b, '0'+byte(exp/100))
+		exp %= 100
+	}
+	return append(b, '0'+byte(exp/10), '0'+byte(exp%10))
+}
+
+// appendDigitsFixed appends the decimal number 0.digits * 10^dp in
+// positional notation with prec digits after the decimal point,
+// padding digits with zeros as needed.
+func appendDigitsFixed(b []byte, neg bool, digits []byte, dp, prec int) []byte {
+	if neg {
+		b = append(b, '-')
+	}
+	nd := len(digits)
+	if dp > 0 {
+		m := nd
+		if m > dp {
+			m = dp
+		}
+		b = append(b, digits[:m]...)
+		b = appendZeros(b, dp-m)
+	} else {
+		b = append(b, '0')
+	}
+	if prec > 0 {
+		b = append(b, '.')
+		for i := 0; i < prec; i++ {
+			j := dp + i
+			if j < 0 || j >= nd {
+				b = append(b, '0')
+			} else {
+				b = append(b, digits[j])
+			}
+		}
+	}
+	return b
+}
+
+// appendExpDigits64 appends the first prec+1 significant digits of the
+// nonzero number m2 * 2^e2, correctly rounded, to b. It returns the extended
+// buffer and the decimal exponent of the first digit.
+func appendExpDigits64(b []byte, m2 uint64, e2 int32, prec int) ([]byte, int32) {
+	start := len(b)
+	precision := uint32(prec) + 1
+	var (
+		digits          uint32
+		printedDigits   uint32
+		availableDigits uint32
+		exp             int32
+	)
+	if e2 >= -52 {
+		var idx uint32
+		if e2 >= 0 {
+			idx = indexForExponent(uint32(e2))
+		}
+		p10bits := pow10BitsForIndex(idx)
+		for i := int32(lengthForIndex(idx)) - 1; i >= 0; i-- {
+			j := int32(p10bits) - e2
+			// Shifting m2 by 8 means that the shift amount is always at
+			// least 128, as mulShiftMod1e9 requires.
+			digits = mulShiftMod1e9(m2<<8, &pow10Split[uint32(pow10Offset[idx])+uint32(i)], j+8)
+			if printedDigits != 0 {
+				if printedDigits+9 > precision {
+					availableDigits = 9
+					break
+				}
+				b = appendDigits32(b, digits, 9)
+				printedDigits += 9
+			} else if digits != 0 {
+				availableDigits = uint32(decimalLen32(digits))
+				exp = i*9 + int32(availableDigits) - 1
+				if availableDigits > precision {
+					break
+				}
+				b = appendDigits32(b, digits, int(availableDigits))
+				printedDigits = availableDigits
+				availableDigits = 0
+			}
+		}
+	}
+
+	if e2 < 0 && availableDigits == 0 {
+		idx := -e2 / 16
+		for i := int32(minBlock2[idx]); i < 200; i++ {
+			j := additionalBits2 + (-e2 - 16*idx)
+			p := uint32(pow10Offset2[idx]) + uint32(i) - uint32(minBlock2[idx])
+			if p >= uint32(pow10Offset2[idx+1]) {
+				digits = 0
+			} else {
+				digits = mulShiftMod1e9(m2<<8, &pow10Split2[p], j+8)
+			}
+			if printedDigits != 0 {
+				if printedDigits+9 > precision {
+					availableDigits = 9
+					break
+				}
+				b = appendDigits32(b, digits, 9)
+				printedDigits += 9
+			} else if digits != 0 {
+				availableDigits = uint32(decimalLen32(digits))
+				exp = -(i+1)*9 + int32(availableDigits) - 1
+				if availableDigits > precision {
+					break
+				}
+				b = appendDigits32(b, digits, int(availableDigits))
+				printedDigits = availableDigits
+				availableDigits = 0
+			}
+		}
+	}
+
+	maximum := precision - printedDigits
+	if availableDigits == 0 {
+		digits = 0
+	}
+	var lastDigit uint32
+	if availableDigits > maximum {
+		for k := uint32(0); k < availableDigits-maximum; k++ {
+			lastDigit = digits % 10
+			digits /= 10
+		}
+	}
+	// 0 = don't round up; 1 = round up unconditionally; 2 = round up if odd.
+	var roundUp int
+	if lastDigit != 5 {
+		roundUp = boolToInt(lastDigit > 5)
+	} else {
+		// Is m2 * 2^e2 * 10^(precision - exp) an integer?
+		rexp := int32(precision) - exp
+		requiredTwos := -e2 - rexp
+		trailingZeros := requiredTwos <= 0 ||
+			(requiredTwos < 60 && multipleOfPowerOfTwo64(m2, uint32(requiredTwos)))
+		if rexp < 0 {
+			trailingZeros = trailingZeros && multipleOfPowerOfFive64(m2, uint32(-rexp))
+		}
+		roundUp = 1
+		if trailingZeros {
+			roundUp = 2
+		}
+	}
+	b = appendDigits32(b, digits, int(maximum))
+
+	if roundUp != 0 {
+		for i := len(b) - 1; ; i-- {
+			if i < start {
+				// All the digits were 9s and are now 0s.
+				b[start] = '1'
+				exp++
+				break
+			}
+			c := b[i]
+			if c == '9' {
+				b[i] = '0'
+				roundUp = 1
+				continue
+			}
+			if roundUp == 2 && c%2 == 0 {
+				break
+			}
+			b[i] = c + 1
+			break
+		}
+	}
+	return b, exp
+}
+
+// appendFixedPrec64 appends the nonzero number m2 * 2^e2 in positional
+// notation with prec digits after the decimal point (d2fixed_buffered_n).
+func appendFixedPrec64(b []byte, m2 uint64, e2 int32, prec int) []byte {
+	start := len(b)
+	nonzero := false
+	if e2 >= -52 {
+		var idx uint32
+		if e2 >= 0 {
+			idx = indexForExponent(uint32(e2))
+		}
+		p10bits := pow10BitsForIndex(idx)
+		for i := int32(lengthForIndex(idx)) - 1; i >= 0; i-- {
+			j := int32(p10bits) - e2
+			digits := mulShiftMod1e9(m2<<8, &pow10Split[uint32(pow10Offset[idx])+uint32(i)], j+8)
+			if nonzero {
+				b = appendDigits32(b, digits, 9)
+			} else if digits != 0 {
+				b = appendDigits32(b, digits, decimalLen32(digits))
+				nonzero = true
+			}
+		}
+	}
+	if !nonzero {
+		b = append(b, '0')
+	}
+	if prec > 0 {
+		b = append(b, '.')
+	}
+	if e2 >= 0 {
+		return appendZeros(b, prec)
+	}
+
+	idx := -e2 / 16
+	blocks := uint32(prec)/9 + 1
+	minBlock := uint32(minBlock2[idx])
+	// 0 = don't round up; 1 = round up unconditionally; 2 = round up if odd.
+	var roundUp int
+	var i uint32
+	if blocks <= minBlock {
+		i = blocks
+		b = appendZeros(b, prec)
+	} else if i < minBlock {
+		i = minBlock
+		b = appendZeros(b, 9*int(i))
+	}
+	for ; i < blocks; i++ {
+		j := additionalBits2 + (-e2 - 16*idx)
+		p := uint32(pow10Offset2[idx]) + i - minBlock
+		if p >= uint32(pow10Offset2[idx+1]) {
+			// The remaining digits are all 0, so no rounding is needed.
+			b = appendZeros(b, prec-9*int(i))
+			break
+		}
+		digits := mulShiftMod1e9(m2<<8, &pow10Split2[p], j+8)
+		if i < blocks-1 {
+			b = appendDigits32(b, digits, 9)
+			continue
+		}
+		maximum := uint32(prec) - 9*i
+		var lastDigit uint32
+		for k := uint32(0); k < 9-maximum; k++ {
+			lastDigit = digits % 10
+			digits /= 10
+		}
+		if lastDigit != 5 {
+			roundUp = boolToInt(lastDigit > 5)
+		} else {
+			// Is m2 * 10^(prec + 1) / 2^(-e2) an integer?
+			requiredTwos := -e2 - int32(prec) - 1
+			trailingZeros := requiredTwos <= 0 ||
+				(requiredTwos < 60 && multipleOfPowerOfTwo64(m2, uint32(requiredTwos)))
+			roundUp = 1
+			if trailingZeros {
+				roundUp = 2
+			}
+		}
+		b = appendDigits32(b, digits, int(maximum))
+		break
+	}
+
+	if roundUp != 0 {
+		dot := -1
+		for i := len(b) - 1; ; i-- {
+			if i < start {
+				// All the digits were 9s and are now 0s:
+				// 99.9 became 00.0 and needs to be 100.0.
+				b[start] = '1'
+				if dot >= 0 {
+					b[dot] = '0'
+					b[dot+1] = '.'
+				}
+				b = append(b, '0')
+				break
+			}
+			c := b[i]
+			if c == '.' {
+				dot = i
+				continue
+			}
+			if c == '9' {
+				b[i] = '0'
+				roundUp = 1
+				continue
+			}
+			if roundUp == 2 && c%2 == 0 {
+				break
+			}
+			b[i] = c + 1
+			break
+		}
+	}
+	return b
+}
+
+func indexForExponent(e uint32) uint32 {
+	return (e + 15) / 16
+}
+
+func pow10BitsForIndex(idx uint32) uint32 {
+	return 16*idx + pow10AdditionalBits
+}
+
+// lengthForIndex returns the number of 9-digit blocks needed for the integer
+// part of a number less than 2^53 * 2^(16*idx).
+func lengthForIndex(idx uint32) uint32 {
+	// +1 for ceil, +16 for mantissa, +8 to round up when dividing by 9
+	return (log10Pow2(16*int32(idx)) + 1 + 16 + 8) / 9
+}
+
+// mulShiftMod1e9 returns ((m * mul) >> j) % 1e9, where mul is a 192-bit
+// number stored as little-endian 64-bit words.
+func mulShiftMod1e9(m uint64, mul *[3]uint64, j int32) uint32 {
+	assert(j >= 128, "j >= 128")
+	assert(j <= 180, "j <= 180")
+	b0hi, _ := bits.Mul64(m, mul[0])
+	b1hi, b1lo := bits.Mul64(m, mul[1])
+	b2hi, b2lo := bits.Mul64(m, mul[2])
+	_, carry := bits.Add64(b1lo, b0hi, 0)
+	mid := b1hi + carry
+	lo, carry := bits.Add64(b2lo, mid, 0)
+	hi := b2hi + carry
+	// Shift the upper 128 bits of the product right by the rest of j.
+	shift := uint(j - 128)
+	lo = lo>>shift | hi<<(64-shift)
+	hi >>= shift
+	return uint128Mod1e9(hi, lo)
+}
+
+// uint128Mod1e9 returns v % 1e9 for a 128-bit number v < 2^100.
+func uint128Mod1e9(hi, lo uint64) uint32 {
+	// Compute v / 1e9 by multiplying with ceil(2^157 / 1e9), which is exact
+	// for the values we need. After multiplying, we're going to shift right
+	// by 29, then truncate to uint32. This means that we need only
+	// 29 + 32 = 61 bits, so we can truncate to uint64 before shifting.
+	const mulHi, mulLo = 0x89705F4136B4A597, 0x31680A88F8953031
+	b00hi, _ := bits.Mul64(lo, mulLo)
+	b01hi, b01lo := bits.Mul64(lo, mulHi)
+	b10hi, b10lo := bits.Mul64(hi, mulLo)
+	_, b11lo := bits.Mul64(hi, mulHi)
+	temp1lo, carry := bits.Add64(b10lo, b00hi, 0)
+	temp1hi := b10hi + carry
+	_, carry = bits.Add64(b01lo, temp1lo, 0)
+	temp2hi := b01hi + carry
+	multiplied := b11lo + temp1hi + temp2hi
+
+	// The quotient may not fit into a uint32, but only the low 32 bits of
+	// v - 1e9*quotient matter.
+	shifted := uint32(multiplied >> 29)
+	return uint32(lo) - 1e9*shifted
+}
+
+// appendDigits32 appends the n least significant decimal digits of v,
+// including any leading zeros, to b.
+func appendDigits32(b []byte, v uint32, n int) []byte {
+	b = extend(b, n)
+	for i := len(b) - 1; i >= len(b)-n; i-- {
+		b[i] = '0' + byte(v%10)
+		v /= 10
+	}
+	return b
+}
+
+func appendZeros(b []byte, n int) []byte {
+	for i := 0; i < n; i++ {
+		b = append(b, '0')
+	}
+	return b
+}
@@ -0,0 +1,103 @@
+// Copyright 2019 Caleb Spare
+//
+// The contents of this file may be used under the terms of the Apache License,
+// Version 2.0.
+//
+//    (See accompanying file LICENSE or copy at
+//     http://www.apache.org/licenses/LICENSE-2.0)
+//
+// Unless required by applicable law or agreed to in writing, this software
+// is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.
+
+package ryu
+
+import (
+	"math"
+	"math/rand"
+	"strconv"
+	"testing"
+)
+
+var precTestCases = []int{-1, 0, 1, 2, 3, 5, 6, 9, 10, 16, 17, 18, 30, 100, 330, 800, 1100}
+
+var precFormats = []byte{'e', 'E', 'f', 'g', 'G'}
+
+func TestFormatFloat64Prec(t *testing.T) {
+	cases := append(genericTestCases, float64TestCases...)
+	cases = append(cases, layoutTestCases...)
+	cases = append(cases,
+		0.5,
+		1.5,
+		2.5,
+		0.125,
+		9.5,
+		99.95,
+		0.0009995,
+		999999999.5,
+		1e308,
+		math.Nextafter(1, 2),
+		math.Nextafter(1, 0),
+	)
+	for _, f := range cases {
+		for _, fmt := range precFormats {
+			for _, prec := range precTestCases {
+				got := FormatFloat64Prec(f, fmt, prec)
+				want := strconv.FormatFloat(f, fmt, prec, 64)
+				if got != want {
+					t.Errorf("FormatFloat64Prec(%g, %q, %d): got %q; want %q",
+						f, fmt, prec, got, want)
+				}
+			}
+		}
+	}
+}
+
+func TestFormatFloat64PrecRandom(t *testing.T) {
+	n := int(1e5)
+	if testing.Short() {
+		n = 1e4
+	}
+	for i := 0; i < n; i++ {
+		f := math.Float64frombits(rand.Uint64())
+		if math.IsNaN(f) || math.IsInf(f, 0) {
+			continue
+		}
+		fmt := precFormats[rand.Intn(len(precFormats))]
+		prec := rand.Intn(20)
+		if fmt == 'f' && rand.Intn(2) == 0 {
+			// Exercise the rounding of numbers with many digits.
+			prec = rand.Intn(1100)
+		}
+		got := FormatFloat64Prec(f, fmt, prec)
+		want := strconv.FormatFloat(f, fmt, prec, 64)
+		if got != want {
+			t.Fatalf("FormatFloat64Prec(%g, %q, %d): got %q; want %q",
+				f, fmt, prec, got, want)
+		}
+	}
+}
+
+func BenchmarkAppendFloat64Prec(b *testing.B) {
+	for _, f := range append(benchCases, benchCases64...) {
+		b.Run(FormatFloat64(f), func(b *testing.B) {
+			var buf []byte
+			for i := 0; i < b.N; i++ {
+				buf = AppendFloat64Prec(buf[:0], f, 'e', 6)
+			}
+			sinkb = buf
+		})
+	}
+}
+
+func BenchmarkStrconvAppendFloat64Prec(b *testing.B) {
+	for _, f := range append(benchCases, benchCases64...) {
+		b.Run(FormatFloat64(f), func(b *testing.B) {
+			var buf []byte
+			for i := 0; i < b.N; i++ {
+				buf = strconv.AppendFloat(buf[:0], f, 'e', 6, 64)
+			}
+			sinkb = buf
+		})
+	}
+}
@@ -0,0 +1,108 @@
+// Copyright 2019 Caleb Spare
+//
+// The contents of this file may be used under the terms of the Apache License,
+// Version 2.0.
+//
+//    (See accompanying file LICENSE or copy at
+//     http://www.apache.org/licenses/LICENSE-2.0)
+//
+// Unless required by applicable law or agreed to in writing, this software
+// is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.
+
+package ryu
+
+import (
+	"math"
+	"reflect"
+	"unsafe"
+)
+
+// FormatFloat64Python converts the 64-bit floating point number f to a
+// string the way Python's repr(float) does. Numbers whose decimal point
+// position is between -4 and 16 (exclusive and inclusive, respectively) are
+// printed in positional notation with at least one digit after the point, as
+// in "1.0" or "0.0001"; others are printed in exponent notation with at least
+// two exponent digits, as in "1e+16" or "1e-05". The infinities and NaN are
+// printed as "inf", "-inf", and "nan".
+func FormatFloat64Python(f float64) string {
+	b := make([]byte, 0, 24)
+	b = AppendFloat64Python(b, f)
+
+	// Convert the output to a string without copying.
+	var s string
+	sh := (*reflect.StringHeader)(unsafe.Pointer(&s))
+	sh.Data = uintptr(unsafe.Pointer(&b[0]))
+	sh.Len = len(b)
+	return s
+}
+
+// AppendFloat64Python appends the string form of the 64-bit floating point
+// number f, as generated by FormatFloat64Python, to b and returns the
+// extended buffer.
+func AppendFloat64Python(b []byte, f float64) []byte {
+	u := math.Float64bits(f)
+	neg := u>>(mantBits64+expBits64) != 0
+	mant := u & (uint64(1)<<mantBits64 - 1)
+	exp := (u >> mantBits64) & (uint64(1)<<expBits64 - 1)
+
+	if exp == uint64(1)<<expBits64-1 {
+		if mant != 0 {
+			return append(b, "nan"...)
+		}
+		if neg {
+			return append(b, "-inf"...)
+		}
+		return append(b, "inf"...)
+	}
+	if neg {
+		b = append(b, '-')
+	}
+	if exp == 0 && mant == 0 {
+		return append(b, '0', '.', '0')
+	}
+
+	d, ok := float64ToDecimalExactInt(mant, exp)
+	if !ok {
+		d = float64ToDecimal(mant, exp)
+	}
+
+	// The value is 0.s * 10^decpt, where s has k digits.
+	var digits [17]byte
+	k := decimalLen64(d.m)
+	writeDigits64(digits[:k], d.m)
+	s := digits[:k]
+	decpt := int(d.e) + k
+
+	switch {
+	case decpt <= -4 || decpt > 16:
+		b = append(b, s[0])
+		if k > 1 {
+			b = append(b, '.')
+			b = append(b, s[1:]...)
+		}
+		b = append(b, 'e')
+		e := decpt - 1
+		if e < 0 {
+			b = append(b, '-')
+			e = -e
+		} else {
+			b = append(b, '+')
+		}
+		if e >= 100 {
+			b = append(b, '0'+byte(e/100))
+		}
+		return append(b, '0'+byte(e/10%10), '0'+byte(e%10))
+	case decpt <= 0:
+		b = append(b, '0', '.')
+		b = appendZeros(b, -decpt)
+		return append(b, s...)
+	case decpt >= k:
+		b = append(b, s...)
+		b = appendZeros(b, decpt-k)
+		return append(b, '.', '0')
+	}
+	b = append(b, s[:decpt]...)
+	b = append(b, '.')
+	return append(b, s[decpt:]...)
+}
@@ -28,6 +28,14 @@ import (
 //go:generate go run maketables.go
 
 const (
+	mantBits16 = 10
+	expBits16  = 5
+	bias16     = 15
+
+	mantBitsBF16 = 7
+	expBitsBF16  = 8
+	biasBF16     = 127
+
 	mantBits32 = 23
 	expBits32  = 8
 	bias32     = 127
@@ -35,6 +43,14 @@ const (
 	mantBits64 = 52
 	expBits64  = 11
 	bias64     = 1023
+
+	mantBits80 = 64 // including the explicit integer bit
+	expBits80  = 15
+	bias80     = 16383
+
+	mantBits128 = 112
+	expBits128  = 15
+	bias128     = 16383
 )
 
 // FormatFloat32 converts a 32-bit floating point number f to a string.
@@ -109,6 +125,193 @@ func AppendFloat64(b []byte, f float64) []byte {
 	return d.append(b, neg)
 }
 
+// AppendFloat32s appends the string forms of the 32-bit floating point
+// numbers in fs, as generated by FormatFloat32 and separated by sep, to b and
+// returns the extended buffer.
+func AppendFloat32s(b []byte, fs []float32, sep byte) []byte {
+	// Make room for the longest possible output up front
+	// so that b is grown at most once.
+	b = grow(b, len(fs)*(15+1))
+	for i, f := range fs {
+		if i > 0 {
+			b = append(b, sep)
+		}
+		b = AppendFloat32(b, f)
+	}
+	return b
+}
+
+// AppendFloat64s appends the string forms of the 64-bit floating point
+// numbers in fs, as generated by FormatFloat64 and separated by sep, to b and
+// returns the extended buffer.
+func AppendFloat64s(b []byte, fs []float64, sep byte) []byte {
+	// Make room for the longest possible output up front
+	// so that b is grown at most once.
+	b = grow(b, len(fs)*(24+1))
+	for i, f := range fs {
+		if i > 0 {
+			b = append(b, sep)
+		}
+		b = AppendFloat64(b, f)
+	}
+	return b
+}
+
+// FormatFloat32Fixed converts a 32-bit floating point number f to a string
+// in positional notation.
+// It behaves like strconv.FormatFloat(float64(f), 'f', -1, 32).
+func FormatFloat32Fixed(f float32) string {
+	b := make([]byte, 0, 15)
+	b = AppendFloat32Fixed(b, f)
+
+	// Convert the output to a string without copying.
+	var s string
+	sh := (*reflect.StringHeader)(unsafe.Pointer(&s))
+	sh.Data = uintptr(unsafe.Pointer(&b[0]))
+	sh.Len = len(b)
+	return s
+}
+
+// AppendFloat32Fixed appends the string form of the 32-bit floating point
+// number f, as generated by FormatFloat32Fixed, to b and returns the extended
+// buffer.
+func AppendFloat32Fixed(b []byte, f float32) []byte {
+	u := math.Float32bits(f)
+	neg := u>>(mantBits32+expBits32) != 0
+	mant := u & (uint32(1)<<mantBits32 - 1)
+	exp := (u >> mantBits32) & (uint32(1)<<expBits32 - 1)
+
+	if exp == uint32(1)<<expBits32-1 || (exp == 0 && mant == 0) {
+		return appendSpecialFixed(b, neg, exp == 0, mant == 0)
+	}
+
+	d, ok := float32ToDecimalExactInt(mant, exp)
+	if !ok {
+		d = float32ToDecimal(mant, exp)
+	}
+	return d.appendFixed(b, neg)
+}
+
+// FormatFloat64Fixed converts a 64-bit floating point number f to a string
+// in positional notation.
+// It behaves like strconv.FormatFloat(f, 'f', -1, 64).
+func FormatFloat64Fixed(f float64) string {
+	b := make([]byte, 0, 24)
+	b = AppendFloat64Fixed(b, f)
+
+	// Convert the output to a string without copying.
+	var s string
+	sh := (*reflect.StringHeader)(unsafe.Pointer(&s))
+	sh.Data = uintptr(unsafe.Pointer(&b[0]))
+	sh.Len = len(b)
+	return s
+}
+
+// AppendFloat64Fixed appends the string form of the 64-bit floating point
+// number f, as generated by FormatFloat64Fixed, to b and returns the extended
+// buffer.
+func AppendFloat64Fixed(b []byte, f float64) []byte {
+	u := math.Float64bits(f)
+	neg := u>>(mantBits64+expBits64) != 0
+	mant := u & (uint64(1)<<mantBits64 - 1)
+	exp := (u >> mantBits64) & (uint64(1)<<expBits64 - 1)
+
+	if exp == uint64(1)<<expBits64-1 || (exp == 0 && mant == 0) {
+		return appendSpecialFixed(b, neg, exp == 0, mant == 0)
+	}
+
+	d, ok := float64ToDecimalExactInt(mant, exp)
+	if !ok {
+		d = float64ToDecimal(mant, exp)
+	}
+	return d.appendFixed(b, neg)
+}
+
+// FormatFloat32General converts a 32-bit floating point number f to a string,
+// using an exponent only for large or small exponents.
+// It behaves like strconv.FormatFloat(float64(f), 'g', -1, 32).
+func FormatFloat32General(f float32) string {
+	b := make([]byte, 0, 15)
+	b = AppendFloat32General(b, f)
+
+	// Convert the output to a string without copying.
+	var s string
+	sh := (*reflect.StringHeader)(unsafe.Pointer(&s))
+	sh.Data = uintptr(unsafe.Pointer(&b[0]))
+	sh.Len = len(b)
+	return s
+}
+
+// AppendFloat32General appends the string form of the 32-bit floating point
+// number f, as generated by FormatFloat32General, to b and returns the
+// extended buffer.
+func AppendFloat32General(b []byte, f float32) []byte {
+	u := math.Float32bits(f)
+	neg := u>>(mantBits32+expBits32) != 0
+	mant := u & (uint32(1)<<mantBits32 - 1)
+	exp := (u >> mantBits32) & (uint32(1)<<expBits32 - 1)
+
+	if exp == uint32(1)<<expBits32-1 || (exp == 0 && mant == 0) {
+		return appendSpecialFixed(b, neg, exp == 0, mant == 0)
+	}
+
+	d, ok := float32ToDecimalExactInt(mant, exp)
+	if !ok {
+		d = float32ToDecimal(mant, exp)
+	}
+	if useExp(d.e, decimalLen32(d.m)) {
+		return d.append(b, neg)
+	}
+	return d.appendFixed(b, neg)
+}
+
+// FormatFloat64General converts a 64-bit floating point number f to a string,
+// using an exponent only for large or small exponents.
+// It behaves like strconv.FormatFloat(f, 'g', -1, 64).
+func FormatFloat64General(f float64) string {
+	b := make([]byte, 0, 24)
+	b = AppendFloat64General(b, f)
+
+	// Convert the output to a string without copying.
+	var s string
+	sh := (*reflect.StringHeader)(unsafe.Pointer(&s))
+	sh.Data = uintptr(unsafe.Pointer(&b[0]))
+	sh.Len = len(b)
+	return s
+}
+
+// AppendFloat64General appends the string form of the 64-bit floating point
+// number f, as generated by FormatFloat64General, to b and returns the
+// extended buffer.
+func AppendFloat64General(b []byte, f float64) []byte {
+	u := math.Float64bits(f)
+	neg := u>>(mantBits64+expBits64) != 0
+	mant := u & (uint64(1)<<mantBits64 - 1)
+	exp := (u >> mantBits64) & (uint64(1)<<expBits64 - 1)
+
+	if exp == uint64(1)<<expBits64-1 || (exp == 0 && mant == 0) {
+		return appendSpecialFixed(b, neg, exp == 0, mant == 0)
+	}
+
+	d, ok := float64ToDecimalExactInt(mant, exp)
+	if !ok {
+		d = float64ToDecimal(mant, exp)
+	}
+	if useExp(d.e, decimalLen64(d.m)) {
+		return d.append(b, neg)
+	}
+	return d.appendFixed(b, neg)
+}
+
+// useExp reports whether the shortest number with n digits and decimal
+// exponent e is printed with an exponent by the 'g' format. Like strconv, it
+// uses the exponent form when the exponent of the leading digit is less than
+// -4 or at least 6 (the default precision of %e).
+func useExp(e int32, n int) bool {
+	exp := e + int32(n) - 1
+	return exp < -4 || exp >= 6
+}
+
 func appendSpecial(b []byte, neg, expZero, mantZero bool) []byte {
 	if !mantZero {
 		return append(b, "NaN"...)
@@ -126,6 +329,38 @@ func appendSpecial(b []byte, neg, expZero, mantZero bool) []byte {
 	return append(b, "0e+00"...)
 }
 
+// appendSpecialFixed is like appendSpecial but writes zero in positional
+// notation.
+func appendSpecialFixed(b []byte, neg, expZero, mantZero bool) []byte {
+	if expZero && mantZero {
+		if neg {
+			b = append(b, '-')
+		}
+		return append(b, '0')
+	}
+	return appendSpecial(b, neg, expZero, mantZero)
+}
+
+// extend grows b by n bytes and returns the extended buffer.
+// The contents of the new bytes are unspecified.
+func extend(b []byte, n int) []byte {
+	if cap(b)-len(b) >= n {
+		// Avoid function call in the common case.
+		return b[:len(b)+n]
+	}
+	return append(b, make([]byte, n)...)
+}
+
+// grow returns b with capacity for at least n more bytes.
+func grow(b []byte, n int) []byte {
+	if cap(b)-len(b) >= n {
+		return b
+	}
+	nb := make([]byte, len(b), len(b)+n)
+	copy(nb, b)
+	return nb
+}
+
 func assert(t bool, msg string) {
 	if !t {
 		panic(msg)
@@ -0,0 +1,412 @@
+// Copyright 2018 Ulf Adams
+// Modifications copyright 2019 Caleb Spare
+//
+// The contents of this file may be used under the terms of the Apache License,
+// Version 2.0.
+//
+//    (See accompanying file LICENSE or copy at
+//     http://www.apache.org/licenses/LICENSE-2.0)
+//
+// Unless required by applicable law or agreed to in writing, this software
+// is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.
+//
+// The code in this file is part of a Go translation of the C code written by
+// Ulf Adams which may be found at https://github.com/ulfjack/ryu. That source
+// code is licensed under Apache 2.0 and this code is derivative work thereof.
+
+package ryu
+
+import (
+	"math/bits"
+	"reflect"
+	"unsafe"
+)
+
+// This file implements the generic 128-bit algorithm (generic_128.c in the
+// C library), which is used for formats whose mantissas do not fit into a
+// uint64.
+
+// FormatFloat128 converts an IEEE 754 quadruple-precision (binary128)
+// floating point number, given by the high and low 64 bits of its
+// representation, to a string. The output has the same form as that of
+// FormatFloat64: the shortest decimal that rounds to the same binary128
+// value, in exponential notation. The exponent has as many digits as needed
+// (up to 4).
+func FormatFloat128(hi, lo uint64) string {
+	b := make([]byte, 0, 44)
+	b = AppendFloat128(b, hi, lo)
+
+	// Convert the output to a string without copying.
+	var s string
+	sh := (*reflect.StringHeader)(unsafe.Pointer(&s))
+	sh.Data = uintptr(unsafe.Pointer(&b[0]))
+	sh.Len = len(b)
+	return s
+}
+
+// AppendFloat128 appends the string form of the quadruple-precision floating
+// point number given by hi and lo, as generated by FormatFloat128, to b and
+// returns the extended buffer.
+func AppendFloat128(b []byte, hi, lo uint64) []byte {
+	// Step 1: Decode the floating-point number.
+	// Unify normalized and subnormal cases.
+	neg := hi>>63 != 0
+	mant := uint128{lo: lo, hi: hi & (uint64(1)<<(mantBits128-64) - 1)}
+	exp := uint32(hi>>(mantBits128-64)) & (uint32(1)<<expBits128 - 1)
+	mantZero := mant.lo == 0 && mant.hi == 0
+
+	// Exit early for easy cases.
+	if exp == uint32(1)<<expBits128-1 || (exp == 0 && mantZero) {
+		return appendSpecial(b, neg, exp == 0, mantZero)
+	}
+
+	var e2 int32
+	m2 := mant
+	if exp == 0 {
+		// We subtract 2 so that the bounds computation has
+		// 2 additional bits.
+		e2 = 1 - bias128 - mantBits128 - 2
+	} else {
+		e2 = int32(exp) - bias128 - mantBits128 - 2
+		m2.hi |= uint64(1) << (mantBits128 - 64)
+	}
+	d := toDecimal128(m2, e2, boolToUint64(!mantZero || exp <= 1))
+	return d.append(b, neg)
+}
+
+// dec128 is a floating decimal type representing m * 10^e.
+type dec128 struct {
+	m uint128
+	e int32
+}
+
+func (d dec128) append(b []byte, neg bool) []byte {
+	// Step 5: Print the decimal representation.
+	if neg {
+		b = append(b, '-')
+	}
+
+	// Print the decimal digits into a scratch buffer from right to left,
+	// 16 at a time (the most that writeDigits64 can handle) until the
+	// remainder fits into a uint64.
+	var buf [40]byte
+	i := len(buf)
+	out := d.m
+	for out.hi != 0 || out.lo >= 1e16 {
+		var r uint64
+		out, r = divMod128(out, 1e16)
+		i -= 16
+		writeDigits64(buf[i:i+16], r)
+	}
+	n := decimalLen64(out.lo)
+	i -= n
+	writeDigits64(buf[i:i+n], out.lo)
+	digits := buf[i:]
+
+	b = append(b, digits[0])
+	if len(digits) > 1 {
+		b = append(b, '.')
+		b = append(b, digits[1:]...)
+	}
+
+	// Print the exponent.
+	b = append(b, 'e')
+	exp := d.e + int32(len(digits)) - 1
+	if exp < 0 {
+		b = append(b, '-')
+		exp = -exp
+	} else {
+		// Unconditionally print a + here to match strconv's formatting.
+		b = append(b, '+')
+	}
+	// Always print at least two digits to match strconv's formatting.
+	var e [4]byte
+	j := len(e)
+	for exp > 0 || j > len(e)-2 {
+		j--
+		e[j] = '0' + byte(exp%10)
+		exp /= 10
+	}
+	return append(b, e[j:]...)
+}
+
+// toDecimal128 computes the shortest decimal representation of m2 * 2^(e2+2).
+// As with toDecimal32, e2 already has 2 subtracted, and mmShift is 0 if the
+// gap to the next lower float is half the gap to the next higher one and 1
+// otherwise. The mantissa m2 may have at most 124 bits.
+func toDecimal128(m2 uint128, e2 int32, mmShift uint64) dec128 {
+	even := m2.lo&1 == 0
+	acceptBounds := even
+
+	// Step 2: Determine the interval of valid decimal representations.
+	var (
+		mv = uint128{lo: m2.lo << 2, hi: m2.hi<<2 | m2.lo>>62}
+		mp = add128(mv, 2)
+		mm = sub128(mv, 1+mmShift)
+	)
+
+	// Step 3: Convert to a decimal power base using 256-bit arithmetic.
+	var (
+		vr, vp, vm        uint128
+		e10               int32
+		vmIsTrailingZeros bool
+		vrIsTrailingZeros bool
+	)
+	if e2 >= 0 {
+		// This expression is slightly faster than
+		// max(0, log10Pow2(e2)-1).
+		q := log10Pow2Generic(e2) - boolToUint32(e2 > 3)
+		e10 = int32(q)
+		k := pow5InvNumBits128 + pow5BitsGeneric(int32(q)) - 1
+		i := -e2 + int32(q) + k
+		pow5 := pow5InvGeneric(q)
+		vr = mulShift128(mv, &pow5, i)
+		vp = mulShift128(mp, &pow5, i)
+		vm = mulShift128(mm, &pow5, i)
+		// floor(log_5(2^128)) = 55, which is very conservative.
+		if q <= 55 {
+			// Only one of mp, mv, and mm can be a multiple of 5, if any.
+			if _, r := divMod128(mv, 5); r == 0 {
+				vrIsTrailingZeros = multipleOfPowerOfFive128(mv, q)
+			} else if acceptBounds {
+				vmIsTrailingZeros = multipleOfPowerOfFive128(mm, q)
+			} else if multipleOfPowerOfFive128(mp, q) {
+				vp = sub128(vp, 1)
+			}
+		}
+	} else {
+		// This expression is slightly faster than
+		// max(0, log10Pow5(-e2)-1).
+		q := log10Pow5Generic(-e2) - boolToUint32(-e2 > 1)
+		e10 = int32(q) + e2
+		i := -e2 - int32(q)
+		k := pow5BitsGeneric(i) - pow5NumBits128
+		j := int32(q) - k
+		pow5 := pow5Generic(uint32(i))
+		vr = mulShift128(mv, &pow5, j)
+		vp = mulShift128(mp, &pow5, j)
+		vm = mulShift128(mm, &pow5, j)
+		if q <= 1 {
+			// {vr,vp,vm} is trailing zeros if {mv,mp,mm} has at
+			// least q trailing 0 bits. mv = 4 * m2, so it always
+			// has at least two trailing 0 bits.
+			vrIsTrailingZeros = true
+			if acceptBounds {
+				// mm = mv - 1 - mmShift, so it has 1 trailing 0 bit
+				// iff mmShift == 1.
+				vmIsTrailingZeros = mmShift == 1
+			} else {
+				// mp = mv + 2, so it always has at least one
+				// trailing 0 bit.
+				vp = sub128(vp, 1)
+			}
+		} else if q < 127 {
+			// We want to know if the full product has at least q
+			// trailing zeros. This is the case iff mv has at least
+			// q trailing 0 bits (because -e2 >= q).
+			vrIsTrailingZeros = multipleOfPowerOfTwo128(mv, q)
+		}
+	}
+
+	// Step 4: Find the shortest decimal representation
+	// in the interval of valid representations.
+	var removed int32
+	var lastRemovedDigit uint64
+	for {
+		vpDiv10, _ := div10(vp)
+		vmDiv10, vmMod10 := div10(vm)
+		if !less128(vmDiv10, vpDiv10) {
+			break
+		}
+		vrDiv10, vrMod10 := div10(vr)
+		vmIsTrailingZeros = vmIsTrailingZeros && vmMod10 == 0
+		vrIsTrailingZeros = vrIsTrailingZeros && lastRemovedDigit == 0
+		lastRemovedDigit = vrMod10
+		vr, vp, vm = vrDiv10, vpDiv10, vmDiv10
+		removed++
+	}
+	if vmIsTrailingZeros {
+		for {
+			vmDiv10, vmMod10 := div10(vm)
+			if vmMod10 != 0 {
+				break
+			}
+			vpDiv10, _ := div10(vp)
+			vrDiv10, vrMod10 := div10(vr)
+			vrIsTrailingZeros = vrIsTrailingZeros && lastRemovedDigit == 0
+			lastRemovedDigit = vrMod10
+			vr, vp, vm = vrDiv10, vpDiv10, vmDiv10
+			removed++
+		}
+	}
+	if vrIsTrailingZeros && lastRemovedDigit == 5 && vr.lo%2 == 0 {
+		// Round even if the exact number is .....50..0.
+		lastRemovedDigit = 4
+	}
+	// We need to take vr + 1 if vr is outside bounds
+	// or we need to round up.
+	out := vr
+	if (vr == vm && (!acceptBounds || !vmIsTrailingZeros)) || lastRemovedDigit >= 5 {
+		out = add128(out, 1)
+	}
+
+	return dec128{m: out, e: e10 + removed}
+}
+
+// pow5Generic returns 5^i with pow5NumBits128 bits (rounded down),
+// like pow5Split64 does for 64-bit floats.
+func pow5Generic(i uint32) [4]uint64 {
+	base := i / pow5TableSize128
+	base2 := base * pow5TableSize128
+	mul := &pow5Split128[base]
+	if i == base2 {
+		return *mul
+	}
+	offset := i - base2
+	delta := pow5BitsGeneric(int32(i)) - pow5BitsGeneric(int32(base2))
+	corr := (pow5Errors128[i/32] >> (2 * (i % 32))) & 3
+	return mul128x256Shift(pow5Table128[offset], mul, uint32(delta), corr)
+}
+
+// pow5InvGeneric returns 2^(floor(log_2(5^i))+pow5InvNumBits128) / 5^i
+// (rounded up), like pow5InvSplit64 does for 64-bit floats.
+func pow5InvGeneric(i uint32) [4]uint64 {
+	base := (i + pow5TableSize128 - 1) / pow5TableSize128
+	base2 := base * pow5TableSize128
+	mul := &pow5InvSplit128[base]
+	if i == base2 {
+		// The table entries are rounded down.
+		r := *mul
+		var c uint64
+		r[0], c = bits.Add64(r[0], 1, 0)
+		r[1], c = bits.Add64(r[1], 0, c)
+		r[2], c = bits.Add64(r[2], 0, c)
+		r[3], _ = bits.Add64(r[3], 0, c)
+		return r
+	}
+	offset := base2 - i
+	delta := pow5BitsGeneric(int32(base2)) - pow5BitsGeneric(int32(i))
+	corr := (pow5InvErrors128[i/32] >> (2 * (i % 32))) & 3
+	return mul128x256Shift(pow5Table128[offset], mul, uint32(delta), corr)
+}
+
+// mul128x256Shift returns (a * b) >> shift, plus corr, truncated to 256 bits.
+func mul128x256Shift(a uint128, b *[4]uint64, shift uint32, corr uint64) [4]uint64 {
+	assert(shift > 0 && shift < 256, "0 < shift < 256")
+
+	// Compute the full 384-bit product.
+	var p [6]uint64
+	for i, ai := range [2]uint64{a.lo, a.hi} {
+		var carry uint64
+		for j, bj := range b {
+			// p[i+j] + ai*bj + carry fits into 128 bits.
+			hi, lo := bits.Mul64(ai, bj)
+			var c uint64
+			lo, c = bits.Add64(lo, p[i+j], 0)
+			hi += c
+			p[i+j], c = bits.Add64(lo, carry, 0)
+			carry = hi + c
+		}
+		p[i+4] = carry
+	}
+
+	var r [4]uint64
+	w, s := shift/64, shift%64
+	for k := range r {
+		if w+uint32(k) < 6 {
+			r[k] = p[w+uint32(k)] >> s
+		}
+		if s != 0 && w+uint32(k)+1 < 6 {
+			r[k] |= p[w+uint32(k)+1] << (64 - s)
+		}
+	}
+	var c uint64
+	r[0], c = bits.Add64(r[0], corr, 0)
+	r[1], c = bits.Add64(r[1], 0, c)
+	r[2], c = bits.Add64(r[2], 0, c)
+	r[3], _ = bits.Add64(r[3], 0, c)
+	return r
+}
+
+func mulShift128(m uint128, mul *[4]uint64, j int32) uint128 {
+	assert(j > 128, "j > 128")
+	r := mul128x256Shift(m, mul, uint32(j), 0)
+	assert(r[2] == 0 && r[3] == 0, "result fits into 128 bits")
+	return uint128{lo: r[0], hi: r[1]}
+}
+
+// log10Pow2Generic returns floor(log_10(2^e)).
+// Unlike log10Pow2, it covers the exponent range of 15-bit exponents.
+func log10Pow2Generic(e int32) uint32 {
+	assert(e >= 0, "e >= 0")
+	assert(e <= 1<<15, "e <= 1<<15")
+	return uint32((uint64(e) * 169464822037455) >> 49)
+}
+
+// log10Pow5Generic returns floor(log_10(5^e)).
+func log10Pow5Generic(e int32) uint32 {
+	assert(e >= 0, "e >= 0")
+	assert(e <= 1<<15, "e <= 1<<15")
+	return uint32((uint64(e) * 196742565691928) >> 48)
+}
+
+// pow5BitsGeneric returns ceil(log_2(5^e)), or else 1 if e==0.
+func pow5BitsGeneric(e int32) int32 {
+	assert(e >= 0, "e >= 0")
+	assert(e <= 1<<15, "e <= 1<<15")
+	return int32((uint64(e)*163391164108059)>>46 + 1)
+}
+
+func add128(x uint128, y uint64) uint128 {
+	lo, c := bits.Add64(x.lo, y, 0)
+	return uint128{lo: lo, hi: x.hi + c}
+}
+
+func sub128(x uint128, y uint64) uint128 {
+	lo, b := bits.Sub64(x.lo, y, 0)
+	return uint128{lo: lo, hi: x.hi - b}
+}
+
+func less128(x, y uint128) bool {
+	return x.hi < y.hi || (x.hi == y.hi && x.lo < y.lo)
+}
+
+// div10 returns x / 10 and x % 10.
+func div10(x uint128) (uint128, uint64) {
+	if x.hi == 0 {
+		// Avoid the slower 128-bit division.
+		return uint128{lo: x.lo / 10}, x.lo % 10
+	}
+	return divMod128(x, 10)
+}
+
+// divMod128 returns x / d and x % d.
+func divMod128(x uint128, d uint64) (uint128, uint64) {
+	hi, r := x.hi/d, x.hi%d
+	lo, r := bits.Div64(r, x.lo, d)
+	return uint128{lo: lo, hi: hi}, r
+}
+
+func pow5Factor128(v uint128) uint32 {
+	for n := uint32(0); ; n++ {
+		q, r := divMod128(v, 5)
+		if r != 0 {
+			return n
+		}
+		v = q
+	}
+}
+
+// multipleOfPowerOfFive128 reports whether v is divisible by 5^p.
+func multipleOfPowerOfFive128(v uint128, p uint32) bool {
+	return pow5Factor128(v) >= p
+}
+
+// multipleOfPowerOfTwo128 reports whether v is divisible by 2^p.
+func multipleOfPowerOfTwo128(v uint128, p uint32) bool {
+	if v.lo == 0 {
+		return 64+uint32(bits.TrailingZeros64(v.hi)) >= p
+	}
+	return uint32(bits.TrailingZeros64(v.lo)) >= p
+}
@@ -0,0 +1,121 @@
+// Copyright 2019 Caleb Spare
+//
+// The contents of this file may be used under the terms of the Apache License,
+// Version 2.0.
+//
+//    (See accompanying file LICENSE or copy at
+//     http://www.apache.org/licenses/LICENSE-2.0)
+//
+// Unless required by applicable law or agreed to in writing, this software
+// is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.
+
+package ryu
+
+import (
+	"reflect"
+	"unsafe"
+)
+
+// FormatFloat16 converts an IEEE 754 half-precision (binary16) floating point
+// number, given by its bits, to a string. The output has the same form as
+// that of FormatFloat32: the shortest decimal that rounds to the same
+// binary16 value, in exponential notation.
+func FormatFloat16(bits uint16) string {
+	b := make([]byte, 0, 11)
+	b = AppendFloat16(b, bits)
+
+	// Convert the output to a string without copying.
+	var s string
+	sh := (*reflect.StringHeader)(unsafe.Pointer(&s))
+	sh.Data = uintptr(unsafe.Pointer(&b[0]))
+	sh.Len = len(b)
+	return s
+}
+
+// AppendFloat16 appends the string form of the half-precision floating point
+// number given by bits, as generated by FormatFloat16, to b and returns the
+// extended buffer.
+func AppendFloat16(b []byte, bits uint16) []byte {
+	// Step 1: Decode the floating-point number.
+	// Unify normalized and subnormal cases.
+	u := uint32(bits)
+	neg := u>>(mantBits16+expBits16) != 0
+	mant := u & (uint32(1)<<mantBits16 - 1)
+	exp := (u >> mantBits16) & (uint32(1)<<expBits16 - 1)
+
+	// Exit early for easy cases.
+	if exp == uint32(1)<<expBits16-1 || (exp == 0 && mant == 0) {
+		return appendSpecial(b, neg, exp == 0, mant == 0)
+	}
+
+	// The binary16 exponent range is a small subset of that of float32,
+	// so we can use the float32 tables. Note that, unlike with float32
+	// and float64, not every integer with an exact representation is
+	// its own shortest representation (the spacing of binary16 values
+	// above 2048 is greater than 1), so there is no integer fast path.
+	var e2 int32
+	var m2 uint32
+	if exp == 0 {
+		// We subtract 2 so that the bounds computation has
+		// 2 additional bits.
+		e2 = 1 - bias16 - mantBits16 - 2
+		m2 = mant
+	} else {
+		e2 = int32(exp) - bias16 - mantBits16 - 2
+		m2 = uint32(1)<<mantBits16 | mant
+	}
+	d := toDecimal32(m2, e2, boolToUint32(mant != 0 || exp <= 1))
+	return d.append(b, neg)
+}
+
+// FormatBFloat16 converts a bfloat16 ("brain floating point") number, given
+// by its bits, to a string. A bfloat16 is the upper half of a float32: it has
+// the same 8-bit exponent but only 7 bits of mantissa. The output is the
+// shortest decimal that rounds to the same bfloat16 value, in the form used
+// by FormatFloat32.
+func FormatBFloat16(bits uint16) string {
+	b := make([]byte, 0, 11)
+	b = AppendBFloat16(b, bits)
+
+	// Convert the output to a string without copying.
+	var s string
+	sh := (*reflect.StringHeader)(unsafe.Pointer(&s))
+	sh.Data = uintptr(unsafe.Pointer(&b[0]))
+	sh.Len = len(b)
+	return s
+}
+
+// AppendBFloat16 appends the string form of the bfloat16 number given by
+// bits, as generated by FormatBFloat16, to b and returns the extended buffer.
+func AppendBFloat16(b []byte, bits uint16) []byte {
+	// Step 1: Decode the floating-point number.
+	// Unify normalized and subnormal cases.
+	u := uint32(bits)
+	neg := u>>(mantBitsBF16+expBitsBF16) != 0
+	mant := u & (uint32(1)<<mantBitsBF16 - 1)
+	exp := (u >> mantBitsBF16) & (uint32(1)<<expBitsBF16 - 1)
+
+	// Exit early for easy cases.
+	if exp == uint32(1)<<expBitsBF16-1 || (exp == 0 && mant == 0) {
+		return appendSpecial(b, neg, exp == 0, mant == 0)
+	}
+
+	// The exponent range is that of float32, so we can use the float32
+	// tables. (The shorter mantissa means that larger powers of 5 are
+	// needed for the largest values; pow5InvSplit32 has extra entries
+	// for them.) As with binary16, there is no integer fast path.
+	var e2 int32
+	var m2 uint32
+	if exp == 0 {
+		// We subtract 2 so that the bounds computation has
+		// 2 additional bits.
+		e2 = 1 - biasBF16 - mantBitsBF16 - 2
+		m2 = mant
+	} else {
+		e2 = int32(exp) - biasBF16 - mantBitsBF16 - 2
+		m2 = uint32(1)<<mantBitsBF16 | mant
+	}
+	d := toDecimal32(m2, e2, boolToUint32(mant != 0 || exp <= 1))
+	return d.append(b, neg)
+}
@@ -73,6 +73,12 @@ func (d dec32) append(b []byte, neg bool) []byte {
 	return b
 }
 
+// appendFixed is like append but prints d in positional notation
+// rather than with an exponent.
+func (d dec32) appendFixed(b []byte, neg bool) []byte {
+	return dec64{m: uint64(d.m), e: d.e}.appendFixed(b, neg)
+}
+
 func float32ToDecimalExactInt(mant, exp uint32) (d dec32, ok bool) {
 	e := exp - bias32
 	if e > mantBits32 {
@@ -103,15 +109,27 @@ func float32ToDecimal(mant, exp uint32) dec32 {
 		e2 = int32(exp) - bias32 - mantBits32 - 2
 		m2 = uint32(1)<<mantBits32 | mant
 	}
+	return toDecimal32(m2, e2, boolToUint32(mant != 0 || exp <= 1))
+}
+
+// toDecimal32 computes the shortest decimal representation of m2 * 2^(e2+2)
+// using the 32-bit tables. The exponent e2 already has 2 subtracted so that
+// the bounds computation has 2 additional bits. mmShift is 0 if the distance
+// to the next lower float is half the distance to the next higher one (that
+// is, if m2 is a power of two with a normal exponent greater than 1) and 1
+// otherwise.
+//
+// It is shared by all formats whose values fit the range of the float32
+// tables: float32, float16, and bfloat16.
+func toDecimal32(m2 uint32, e2 int32, mmShift uint32) dec32 {
 	even := m2&1 == 0
 	acceptBounds := even
 
 	// Step 2: Determine the interval of valid decimal representations.
 	var (
-		mv      = 4 * m2
-		mp      = 4*m2 + 2
-		mmShift = boolToUint32(mant != 0 || exp <= 1)
-		mm      = 4*m2 - 1 - mmShift
+		mv = 4 * m2
+		mp = 4*m2 + 2
+		mm = 4*m2 - 1 - mmShift
 	)
 
 	// Step 3: Convert to a decimal power base using 64-bit arithmetic.
@@ -102,6 +102,67 @@ func (d dec64) append(b []byte, neg bool) []byte {
 	return b
 }
 
+// appendFixed is like append but prints d in positional notation
+// rather than with an exponent.
+func (d dec64) appendFixed(b []byte, neg bool) []byte {
+	if neg {
+		b = append(b, '-')
+	}
+
+	outLen := decimalLen64(d.m)
+	n := len(b)
+	// point is the number of digits before the decimal point.
+	point := outLen + int(d.e)
+	switch {
+	case d.e >= 0:
+		// An integer: the digits followed by d.e zeros.
+		b = extend(b, point)
+		writeDigits64(b[n:n+outLen], d.m)
+		for i := n + outLen; i < len(b); i++ {
+			b[i] = '0'
+		}
+	case point > 0:
+		// Print the digits one byte to the right and then
+		// move the integer part left to make room for the '.'.
+		b = extend(b, outLen+1)
+		writeDigits64(b[n+1:], d.m)
+		copy(b[n:], b[n+1:n+1+point])
+		b[n+point] = '.'
+	default:
+		// The number is less than 1: "0.", then zeros, then the digits.
+		b = extend(b, 2-point+outLen)
+		b[n] = '0'
+		b[n+1] = '.'
+		for i := n + 2; i < n+2-point; i++ {
+			b[i] = '0'
+		}
+		writeDigits64(b[n+2-point:], d.m)
+	}
+	return b
+}
+
+// writeDigits64 writes the decimal digits of m into b,
+// which must have length decimalLen64(m).
+func writeDigits64(b []byte, m uint64) {
+	// Avoid expensive 64-bit divisions, as in append.
+	i := len(b) - 1
+	if m>>32 > 0 {
+		var m32 uint32
+		m, m32 = m/1e8, uint32(m%1e8)
+		for j := 0; j < 8; j++ {
+			b[i] = '0' + byte(m32%10)
+			m32 /= 10
+			i--
+		}
+	}
+	m32 := uint32(m)
+	for ; i > 0; i-- {
+		b[i] = '0' + byte(m32%10)
+		m32 /= 10
+	}
+	b[0] = '0' + byte(m32)
+}
+
 func float64ToDecimalExactInt(mant, exp uint64) (d dec64, ok bool) {
 	e := exp - bias64
 	if e > mantBits64 {
@@ -156,7 +217,7 @@ func float64ToDecimal(mant, exp uint64) dec64 {
 		e10 = int32(q)
 		k := pow5InvNumBits64 + pow5Bits(int32(q)) - 1
 		i := -e2 + int32(q) + k
-		mul := pow5InvSplit64[q]
+		mul := pow5InvMul64(q)
 		vr = mulShift64(4*m2, mul, i)
 		vp = mulShift64(4*m2+2, mul, i)
 		vm = mulShift64(4*m2-1-mmShift, mul, i)
@@ -183,7 +244,7 @@ func float64ToDecimal(mant, exp uint64) dec64 {
 		i := -e2 - int32(q)
 		k := pow5Bits(i) - pow5NumBits64
 		j := int32(q) - k
-		mul := pow5Split64[i]
+		mul := pow5Mul64(uint32(i))
 		vr = mulShift64(4*m2, mul, j)
 		vp = mulShift64(4*m2+2, mul, j)
 		vm = mulShift64(4*m2-1-mmShift, mul, j)
@@ -0,0 +1,85 @@
+// Copyright 2019 Caleb Spare
+//
+// The contents of this file may be used under the terms of the Apache License,
+// Version 2.0.
+//
+//    (See accompanying file LICENSE or copy at
+//     http://www.apache.org/licenses/LICENSE-2.0)
+//
+// Unless required by applicable law or agreed to in writing, this software
+// is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.
+
+package ryu
+
+import (
+	"reflect"
+	"unsafe"
+)
+
+// FormatFloat80 converts an x87 80-bit extended precision floating point
+// number to a string. The number is given by its 64-bit mantissa mant, which
+// includes the explicit integer bit as its most significant bit, and by
+// signExp, which holds the sign bit followed by the 15-bit exponent. (On
+// little-endian machines, these are the first 8 and the last 2 bytes of the
+// 10-byte value.) The output has the same form as that of FormatFloat64: the
+// shortest decimal that rounds to the same 80-bit value, in exponential
+// notation.
+//
+// The encodings that the x87 FPU does not generate itself are reported as
+// "NaN": pseudo-denormals (a zero exponent with the integer bit set),
+// unnormals (a nonzero exponent with the integer bit clear),
+// pseudo-infinities, and pseudo-NaNs.
+func FormatFloat80(mant uint64, signExp uint16) string {
+	b := make([]byte, 0, 30)
+	b = AppendFloat80(b, mant, signExp)
+
+	// Convert the output to a string without copying.
+	var s string
+	sh := (*reflect.StringHeader)(unsafe.Pointer(&s))
+	sh.Data = uintptr(unsafe.Pointer(&b[0]))
+	sh.Len = len(b)
+	return s
+}
+
+// AppendFloat80 appends the string form of the 80-bit extended precision
+// floating point number given by mant and signExp, as generated by
+// FormatFloat80, to b and returns the extended buffer.
+func AppendFloat80(b []byte, mant uint64, signExp uint16) []byte {
+	// Step 1: Decode the floating-point number.
+	// Unlike the IEEE interchange formats, the leading bit of the
+	// mantissa is stored explicitly, so it needs to be checked.
+	neg := signExp>>expBits80 != 0
+	exp := uint32(signExp) & (uint32(1)<<expBits80 - 1)
+	intBit := mant>>(mantBits80-1) != 0
+	frac := mant & (uint64(1)<<(mantBits80-1) - 1)
+
+	// Exit early for easy cases.
+	switch {
+	case exp == uint32(1)<<expBits80-1:
+		if !intBit {
+			// Pseudo-infinity or pseudo-NaN.
+			return append(b, "NaN"...)
+		}
+		return appendSpecial(b, neg, false, frac == 0)
+	case (exp != 0) != intBit:
+		// Unnormal or pseudo-denormal.
+		return append(b, "NaN"...)
+	case exp == 0 && mant == 0:
+		return appendSpecial(b, neg, true, true)
+	}
+
+	// The mantissa already includes the leading bit, so there is no
+	// need to distinguish normal and subnormal numbers beyond the
+	// exponent adjustment.
+	var e2 int32
+	if exp == 0 {
+		// We subtract 2 so that the bounds computation has
+		// 2 additional bits.
+		e2 = 1 - bias80 - (mantBits80 - 1) - 2
+	} else {
+		e2 = int32(exp) - bias80 - (mantBits80 - 1) - 2
+	}
+	d := toDecimal128(uint128{lo: mant}, e2, boolToUint64(frac != 0 || exp <= 1))
+	return d.append(b, neg)
+}
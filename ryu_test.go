@@ -19,15 +19,23 @@ package ryu
 
 import (
 	"bytes"
+	"encoding/json"
 	"fmt"
 	"math"
 	"math/big"
 	"math/rand"
+	"os"
+	"runtime"
 	"sort"
 	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
 	"testing"
 	"text/tabwriter"
 	"time"
+
+	"github.com/cespare/ryu/verify"
 )
 
 var genericTestCases = []float64{
@@ -66,7 +74,7 @@ func TestFormatFloat32(t *testing.T) {
 	for _, f64 := range genericTestCases {
 		f := float32(f64)
 		got := FormatFloat32(f)
-		want := strconv.FormatFloat(float64(f), 'e', -1, 32)
+		want := shortestString32(f)
 		if got != want {
 			t.Errorf("FormatFloat32(%g): got %q; want %q", f, got, want)
 		}
@@ -85,41 +93,845 @@ var float64TestCases = []float64{
 	2.2250738585072012e-308,
 	// https://www.exploringbinary.com/php-hangs-on-numeric-value-2-2250738585072011e-308/
 	2.2250738585072011e-308,
+}
 
-	// https://github.com/golang/go/issues/29491
-	//498484681984085570,
-	//-5.8339553793802237e+23,
+// strconvBugTestCases are float64s for which strconv does not print the
+// closest shortest decimal before Go 1.17 (https://golang.org/issue/29491).
+// They are only checked against the verify package.
+var strconvBugTestCases = []float64{
+	498484681984085570,
+	-5.8339553793802237e+23,
 }
 
 func TestFormatFloat64(t *testing.T) {
-	for _, f := range append(genericTestCases, float64TestCases...) {
+	cases := append(genericTestCases, float64TestCases...)
+	for _, f := range append(cases, strconvBugTestCases...) {
 		got := FormatFloat64(f)
-		want := strconv.FormatFloat(f, 'e', -1, 64)
+		want := shortestString64(f)
 		if got != want {
 			t.Errorf("FormatFloat64(%g): got %q; want %q", f, got, want)
 		}
 	}
 }
 
+var layoutTestCases = []float64{
+	0.000012,
+	0.0001,
+	0.00001,
+	100000,
+	0.1,
+	0.5,
+	1.5,
+	12,
+	123.45,
+	123456789,
+	1e15,
+	1e16,
+	1e20,
+	1e21,
+	1.5e-7,
+	123456789012345680000,
+}
+
+func TestFormatFloat32Fixed(t *testing.T) {
+	for _, f64 := range append(genericTestCases, layoutTestCases...) {
+		f := float32(f64)
+		got := FormatFloat32Fixed(f)
+		want := strconv.FormatFloat(float64(f), 'f', -1, 32)
+		if got != want {
+			t.Errorf("FormatFloat32Fixed(%g): got %q; want %q", f, got, want)
+		}
+	}
+}
+
+func TestFormatFloat64Fixed(t *testing.T) {
+	cases := append(genericTestCases, float64TestCases...)
+	for _, f := range append(cases, layoutTestCases...) {
+		got := FormatFloat64Fixed(f)
+		want := strconv.FormatFloat(f, 'f', -1, 64)
+		if got != want {
+			t.Errorf("FormatFloat64Fixed(%g): got %q; want %q", f, got, want)
+		}
+	}
+}
+
+func TestFormatFloat32General(t *testing.T) {
+	for _, f64 := range append(genericTestCases, layoutTestCases...) {
+		f := float32(f64)
+		got := FormatFloat32General(f)
+		want := strconv.FormatFloat(float64(f), 'g', -1, 32)
+		if got != want {
+			t.Errorf("FormatFloat32General(%g): got %q; want %q", f, got, want)
+		}
+	}
+}
+
+func TestFormatFloat64General(t *testing.T) {
+	cases := append(genericTestCases, float64TestCases...)
+	for _, f := range append(cases, layoutTestCases...) {
+		got := FormatFloat64General(f)
+		want := strconv.FormatFloat(f, 'g', -1, 64)
+		if got != want {
+			t.Errorf("FormatFloat64General(%g): got %q; want %q", f, got, want)
+		}
+	}
+}
+
+func TestFormatComplex128(t *testing.T) {
+	for _, re := range genericTestCases {
+		for _, im := range genericTestCases {
+			c := complex(re, im)
+			for _, fmt := range []byte("eEfgG") {
+				got := FormatComplex128(c, fmt)
+				want := strconv.FormatComplex(c, fmt, -1, 128)
+				if got != want {
+					t.Errorf("FormatComplex128(%v, %q): got %q; want %q", c, fmt, got, want)
+				}
+			}
+		}
+	}
+}
+
+func TestFormatComplex64(t *testing.T) {
+	for _, re := range genericTestCases {
+		for _, im := range genericTestCases {
+			c := complex64(complex(re, im))
+			for _, fmt := range []byte("eEfgG") {
+				got := FormatComplex64(c, fmt)
+				want := strconv.FormatComplex(complex128(c), fmt, -1, 64)
+				if got != want {
+					t.Errorf("FormatComplex64(%v, %q): got %q; want %q", c, fmt, got, want)
+				}
+			}
+		}
+	}
+}
+
+func TestFormatFloat64JS(t *testing.T) {
+	for _, tt := range []struct {
+		f    float64
+		want string
+	}{
+		{0, "0"},
+		{math.Copysign(0, -1), "0"},
+		{math.NaN(), "NaN"},
+		{math.Inf(1), "Infinity"},
+		{math.Inf(-1), "-Infinity"},
+		{1, "1"},
+		{-1.5, "-1.5"},
+		{0.1, "0.1"},
+		{1.0 / 3, "0.3333333333333333"},
+		{123.456, "123.456"},
+		{1 << 53, "9007199254740992"},
+		{123456789012345680000, "123456789012345680000"},
+		{1e20, "100000000000000000000"},
+		{999999999999999900000, "999999999999999900000"},
+		{1e21, "1e+21"},
+		{1.5e21, "1.5e+21"},
+		{-1e21, "-1e+21"},
+		{1e-6, "0.000001"},
+		{1.234e-6, "0.000001234"},
+		{1e-7, "1e-7"},
+		{1.5e-7, "1.5e-7"},
+		{-1.5e-7, "-1.5e-7"},
+		{1e100, "1e+100"},
+		{5e-324, "5e-324"},
+		{math.MaxFloat64, "1.7976931348623157e+308"},
+	} {
+		got := FormatFloat64JS(tt.f)
+		if got != tt.want {
+			t.Errorf("FormatFloat64JS(%g): got %q; want %q", tt.f, got, tt.want)
+		}
+	}
+}
+
+func TestFormatFloat64JSRoundTrip(t *testing.T) {
+	for i := 0; i < 1e5; i++ {
+		f := math.Float64frombits(rand.Uint64())
+		if math.IsNaN(f) || math.IsInf(f, 0) {
+			continue
+		}
+		s := FormatFloat64JS(f)
+		g, err := strconv.ParseFloat(s, 64)
+		if err != nil || g != f {
+			t.Fatalf("FormatFloat64JS(%g) = %q, which parses as %g (err=%v)", f, s, g, err)
+		}
+	}
+}
+
+func TestFormatFloat64Python(t *testing.T) {
+	for _, tt := range []struct {
+		f    float64
+		want string
+	}{
+		{0, "0.0"},
+		{math.Copysign(0, -1), "-0.0"},
+		{math.NaN(), "nan"},
+		{math.Inf(1), "inf"},
+		{math.Inf(-1), "-inf"},
+		{1, "1.0"},
+		{-1.5, "-1.5"},
+		{0.1, "0.1"},
+		{1.0 / 3, "0.3333333333333333"},
+		{123.456, "123.456"},
+		{1 << 53, "9007199254740992.0"},
+		{1e15, "1000000000000000.0"},
+		{9999999999999998, "9999999999999998.0"},
+		{1e16, "1e+16"},
+		{1e22, "1e+22"},
+		{123456789012345678, "1.2345678901234568e+17"},
+		{0.0001, "0.0001"},
+		{0.00012, "0.00012"},
+		{0.00001, "1e-05"},
+		{-1.5e-5, "-1.5e-05"},
+		{1e100, "1e+100"},
+		{5e-324, "5e-324"},
+		{math.MaxFloat64, "1.7976931348623157e+308"},
+	} {
+		got := FormatFloat64Python(tt.f)
+		if got != tt.want {
+			t.Errorf("FormatFloat64Python(%g): got %q; want %q", tt.f, got, tt.want)
+		}
+	}
+}
+
+func TestFormatFloat64PythonRoundTrip(t *testing.T) {
+	for i := 0; i < 1e5; i++ {
+		f := math.Float64frombits(rand.Uint64())
+		if math.IsNaN(f) || math.IsInf(f, 0) {
+			continue
+		}
+		s := FormatFloat64Python(f)
+		g, err := strconv.ParseFloat(s, 64)
+		if err != nil || g != f {
+			t.Fatalf("FormatFloat64Python(%g) = %q, which parses as %g (err=%v)", f, s, g, err)
+		}
+	}
+}
+
+var jsonTestCases = []float64{
+	1e-6,
+	1e-7,
+	-1.5e-7,
+	9.999999e-7,
+	1e20,
+	1e21,
+	-1e21,
+	999999999999999900000,
+	1.2345e-10,
+	1e-100,
+}
+
+func TestAppendJSONFloat64(t *testing.T) {
+	cases := append(genericTestCases, float64TestCases...)
+	cases = append(cases, layoutTestCases...)
+	cases = append(cases, jsonTestCases...)
+	for i := 0; i < 1e4; i++ {
+		cases = append(cases, math.Float64frombits(rand.Uint64()))
+	}
+	for _, f := range cases {
+		got, err := AppendJSONFloat64(nil, f)
+		want, wantErr := json.Marshal(f)
+		if (err == nil) != (wantErr == nil) {
+			t.Fatalf("AppendJSONFloat64(%g): got err=%v; want err=%v", f, err, wantErr)
+		}
+		if err != nil {
+			if err.Error() != wantErr.Error() {
+				t.Errorf("AppendJSONFloat64(%g): got err=%q; want %q", f, err, wantErr)
+			}
+			continue
+		}
+		if string(got) != string(want) {
+			t.Errorf("AppendJSONFloat64(%g): got %q; want %q", f, got, want)
+		}
+	}
+}
+
+func TestAppendJSONFloat32(t *testing.T) {
+	cases := append(genericTestCases, layoutTestCases...)
+	cases = append(cases, jsonTestCases...)
+	cases = append(cases, 1e21*(1-1e-7), 1e-6*(1-1e-7))
+	for i := 0; i < 1e4; i++ {
+		cases = append(cases, float64(math.Float32frombits(rand.Uint32())))
+	}
+	for _, f64 := range cases {
+		f := float32(f64)
+		got, err := AppendJSONFloat32(nil, f)
+		want, wantErr := json.Marshal(f)
+		if (err == nil) != (wantErr == nil) {
+			t.Fatalf("AppendJSONFloat32(%g): got err=%v; want err=%v", f, err, wantErr)
+		}
+		if err != nil {
+			if err.Error() != wantErr.Error() {
+				t.Errorf("AppendJSONFloat32(%g): got err=%q; want %q", f, err, wantErr)
+			}
+			continue
+		}
+		if string(got) != string(want) {
+			t.Errorf("AppendJSONFloat32(%g): got %q; want %q", f, got, want)
+		}
+	}
+}
+
+func TestDecimal32(t *testing.T) {
+	for _, f64 := range append(genericTestCases, layoutTestCases...) {
+		f := float32(f64)
+		digits, exp, neg, kind := Decimal32(f)
+		want := shortestString32(f)
+		var got string
+		switch kind {
+		case KindFinite:
+			got = decimalString(uint64(digits), exp, neg)
+		case KindZero:
+			got = decimalString(0, 0, neg)
+		case KindInf:
+			got = shortestString64(math.Inf(int(boolSign(neg))))
+		case KindNaN:
+			got = "NaN"
+		}
+		if got != want {
+			t.Errorf("Decimal32(%g): got %d, %d, %t, %s; want %s",
+				f, digits, exp, neg, kind, want)
+		}
+	}
+}
+
+func TestDecimal64(t *testing.T) {
+	cases := append(genericTestCases, float64TestCases...)
+	cases = append(cases, strconvBugTestCases...)
+	for _, f := range append(cases, layoutTestCases...) {
+		digits, exp, neg, kind := Decimal64(f)
+		want := shortestString64(f)
+		var got string
+		switch kind {
+		case KindFinite:
+			got = decimalString(digits, exp, neg)
+		case KindZero:
+			got = decimalString(0, 0, neg)
+		case KindInf:
+			got = shortestString64(math.Inf(int(boolSign(neg))))
+		case KindNaN:
+			got = "NaN"
+		}
+		if got != want {
+			t.Errorf("Decimal64(%g): got %d, %d, %t, %s; want %s",
+				f, digits, exp, neg, kind, want)
+		}
+	}
+}
+
+// decimalString formats digits * 10^exp like strconv's 'e' format.
+func decimalString(digits uint64, exp int32, neg bool) string {
+	s := strconv.FormatUint(digits, 10)
+	exp += int32(len(s)) - 1
+	if len(s) > 1 {
+		s = s[:1] + "." + s[1:]
+	}
+	if neg {
+		s = "-" + s
+	}
+	return fmt.Sprintf("%se%+03d", s, exp)
+}
+
+func boolSign(neg bool) float64 {
+	if neg {
+		return -1
+	}
+	return 1
+}
+
+// shortestString64 formats f like FormatFloat64 using the verify package
+// rather than strconv, which does not always print the closest shortest
+// decimal before Go 1.17 (https://golang.org/issue/29491).
+func shortestString64(f float64) string {
+	switch {
+	case math.IsNaN(f):
+		return "NaN"
+	case math.IsInf(f, 0):
+		return fmt.Sprintf("%+v", f)
+	case f == 0:
+		return decimalString(0, 0, math.Signbit(f))
+	}
+	digits, exp := verify.ShortestDecimal64(f)
+	return decimalString(digits, int32(exp), math.Signbit(f))
+}
+
+// shortestString32 is like shortestString64 for FormatFloat32.
+func shortestString32(f float32) string {
+	if f != 0 && !math.IsInf(float64(f), 0) && !math.IsNaN(float64(f)) {
+		digits, exp := verify.ShortestDecimal32(f)
+		return decimalString(uint64(digits), int32(exp), math.Signbit(float64(f)))
+	}
+	return shortestString64(float64(f))
+}
+
+func TestFormatFloat16(t *testing.T) {
+	for _, tt := range []struct {
+		bits uint16
+		want string
+	}{
+		{0x0000, "0e+00"},
+		{0x8000, "-0e+00"},
+		{0x7c00, "+Inf"},
+		{0xfc00, "-Inf"},
+		{0x7e00, "NaN"},
+		{0x3c00, "1e+00"},
+		{0xbc00, "-1e+00"},
+		{0x3555, "3.333e-01"},
+		{0x6800, "2.048e+03"},
+		{0x6801, "2.05e+03"},
+		{0x7bff, "6.55e+04"},  // largest finite
+		{0x0400, "6.104e-05"}, // smallest normal
+		{0x03ff, "6.1e-05"},   // largest subnormal
+		{0x0001, "6e-08"},     // smallest subnormal
+	} {
+		got := FormatFloat16(tt.bits)
+		if got != tt.want {
+			t.Errorf("FormatFloat16(%#04x): got %q; want %q", tt.bits, got, tt.want)
+		}
+	}
+}
+
+func TestFormatBFloat16(t *testing.T) {
+	for _, tt := range []struct {
+		bits uint16
+		want string
+	}{
+		{0x0000, "0e+00"},
+		{0x8000, "-0e+00"},
+		{0x7f80, "+Inf"},
+		{0xff80, "-Inf"},
+		{0x7fc0, "NaN"},
+		{0x3f80, "1e+00"},
+		{0xbf80, "-1e+00"},
+		{0x4049, "3.14e+00"},
+		{0x4380, "2.56e+02"},
+		{0x4381, "2.58e+02"},
+		{0x7f7f, "3.39e+38"}, // largest finite
+		{0x0080, "1.18e-38"}, // smallest normal
+		{0x007f, "1.17e-38"}, // largest subnormal
+		{0x0001, "1e-40"},    // smallest subnormal
+	} {
+		got := FormatBFloat16(tt.bits)
+		if got != tt.want {
+			t.Errorf("FormatBFloat16(%#04x): got %q; want %q", tt.bits, got, tt.want)
+		}
+	}
+}
+
+func TestFormatFloat16Exhaustive(t *testing.T) {
+	testFormat16Exhaustive(t, "FormatFloat16", FormatFloat16, mantBits16, expBits16, bias16)
+}
+
+func TestFormatBFloat16Exhaustive(t *testing.T) {
+	testFormat16Exhaustive(t, "FormatBFloat16", FormatBFloat16, mantBitsBF16, expBitsBF16, biasBF16)
+}
+
+func testFormat16Exhaustive(t *testing.T, name string, format func(uint16) string, mantBits, expBits uint, bias int) {
+	for i := 0; i < 1<<16; i++ {
+		bits := uint16(i)
+		neg := bits>>15 != 0
+		mant := uint64(bits) & (1<<mantBits - 1)
+		exp := int(bits>>mantBits) & (1<<expBits - 1)
+		var want string
+		switch {
+		case exp == 1<<expBits-1 && mant == 0:
+			want = "+Inf"
+			if neg {
+				want = "-Inf"
+			}
+		case exp == 1<<expBits-1:
+			want = "NaN"
+		case exp == 0 && mant == 0:
+			want = "0e+00"
+			if neg {
+				want = "-0e+00"
+			}
+		default:
+			want = refShortestString(neg, new(big.Int).SetUint64(mant), exp, mantBits, bias)
+		}
+		got := format(bits)
+		if got != want {
+			t.Errorf("%s(%#04x): got %q; want %q", name, bits, got, want)
+		}
+	}
+}
+
+// TestFormatFloat32Exhaustive checks FormatFloat32 against the verify
+// package for every float32. It takes hours, so it only runs if the
+// environment variable RYU_EXHAUSTIVE_FLOAT32 is set:
+//
+//	RYU_EXHAUSTIVE_FLOAT32=1 go test -run Float32Exhaustive -v -timeout 0
+func TestFormatFloat32Exhaustive(t *testing.T) {
+	if os.Getenv("RYU_EXHAUSTIVE_FLOAT32") == "" {
+		t.Skip("set RYU_EXHAUSTIVE_FLOAT32 to run")
+	}
+	// Each worker checks chunks of positive floats (and their negations)
+	// until all are done or there were too many failures.
+	const (
+		chunkSize   = 1 << 20
+		numChunks   = 1 << 31 / chunkSize
+		maxFailures = 100
+	)
+	var (
+		next     uint64 // next chunk
+		failures uint64
+		wg       sync.WaitGroup
+	)
+	start := time.Now()
+	for w := 0; w < runtime.GOMAXPROCS(0); w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for {
+				chunk := atomic.AddUint64(&next, 1) - 1
+				if chunk >= numChunks || atomic.LoadUint64(&failures) >= maxFailures {
+					return
+				}
+				for i := uint32(0); i < chunkSize; i++ {
+					bits := uint32(chunk)*chunkSize + i
+					if err := checkFloat32(bits); err != nil {
+						t.Error(err)
+						atomic.AddUint64(&failures, 1)
+					}
+				}
+				if chunk%(numChunks/64) == 0 {
+					t.Logf("%.1f%% done after %s", 100*float64(chunk)/numChunks, time.Since(start))
+				}
+			}
+		}()
+	}
+	wg.Wait()
+}
+
+// checkFloat32 checks the result of FormatFloat32 for the positive float32
+// with the given bits and for its negation.
+func checkFloat32(bits uint32) error {
+	f := math.Float32frombits(bits)
+	for _, f := range []float32{f, -f} {
+		if got, want := FormatFloat32(f), shortestString32(f); got != want {
+			return fmt.Errorf("FormatFloat32(%#08x): got %q; want %q", math.Float32bits(f), got, want)
+		}
+	}
+	return nil
+}
+
+func TestFormatFloat128(t *testing.T) {
+	for _, tt := range []struct {
+		hi, lo uint64
+		want   string
+	}{
+		{0x0000000000000000, 0, "0e+00"},
+		{0x8000000000000000, 0, "-0e+00"},
+		{0x7fff000000000000, 0, "+Inf"},
+		{0xffff000000000000, 0, "-Inf"},
+		{0x7fff800000000000, 0, "NaN"},
+		{0x3fff000000000000, 0, "1e+00"},
+		{0xc000000000000000, 0, "-2e+00"},
+		{0x3ffb999999999999, 0x999999999999999a, "1e-01"},
+		{0x4000921fb54442d1, 0x8469898cc51701b8, "3.1415926535897932384626433832795028e+00"},
+		{0x7ffeffffffffffff, 0xffffffffffffffff, "1.189731495357231765085759326628007e+4932"}, // largest finite
+		{0x0001000000000000, 0, "3.3621031431120935062626778173217526e-4932"},                 // smallest normal
+		{0x0000ffffffffffff, 0xffffffffffffffff, "3.362103143112093506262677817321752e-4932"}, // largest subnormal
+		{0x0000000000000000, 1, "6e-4966"},                                                    // smallest subnormal
+	} {
+		got := FormatFloat128(tt.hi, tt.lo)
+		if got != tt.want {
+			t.Errorf("FormatFloat128(%#016x, %#016x): got %q; want %q", tt.hi, tt.lo, got, tt.want)
+		}
+	}
+}
+
+func TestFormatFloat128Ref(t *testing.T) {
+	// Check a sample of mantissas for exponents around the interesting
+	// boundaries (subnormals, 1, integers that need all mantissa bits,
+	// and the largest finite numbers) and a sample of random numbers.
+	exps := []uint64{0, 1, 2, bias128 - 1, bias128, bias128 + 1, 1<<expBits128 - 3, 1<<expBits128 - 2}
+	for e := uint64(bias128 + mantBits128 - 8); e <= bias128+mantBits128+8; e++ {
+		exps = append(exps, e)
+	}
+	r := rand.New(rand.NewSource(1))
+	type f128 struct{ hi, lo uint64 }
+	var cases []f128
+	for _, e := range exps {
+		for _, m := range []f128{
+			{0, 0},
+			{0, 1},
+			{0, 1 << 63},
+			{1<<(mantBits128-64) - 1, 1<<64 - 1},
+			{r.Uint64() & (1<<(mantBits128-64) - 1), r.Uint64()},
+		} {
+			cases = append(cases, f128{e<<(mantBits128-64) | m.hi, m.lo})
+		}
+	}
+	n := 1000
+	if testing.Short() {
+		n = 100
+	}
+	for i := 0; i < n; i++ {
+		cases = append(cases, f128{r.Uint64(), r.Uint64()})
+	}
+	for _, c := range cases {
+		exp := int(c.hi>>(mantBits128-64)) & (1<<expBits128 - 1)
+		if exp == 1<<expBits128-1 || (exp == 0 && c.hi<<16 == 0 && c.lo == 0) {
+			continue
+		}
+		mant := new(big.Int).SetUint64(c.hi & (1<<(mantBits128-64) - 1))
+		mant.Lsh(mant, 64)
+		mant.Or(mant, new(big.Int).SetUint64(c.lo))
+		want := refShortestString(c.hi>>63 != 0, mant, exp, mantBits128, bias128)
+		got := FormatFloat128(c.hi, c.lo)
+		if got != want {
+			t.Errorf("FormatFloat128(%#016x, %#016x): got %q; want %q", c.hi, c.lo, got, want)
+		}
+	}
+}
+
+func TestFormatFloat80(t *testing.T) {
+	for _, tt := range []struct {
+		mant    uint64
+		signExp uint16
+		want    string
+	}{
+		{0, 0x0000, "0e+00"},
+		{0, 0x8000, "-0e+00"},
+		{0x8000000000000000, 0x7fff, "+Inf"},
+		{0x8000000000000000, 0xffff, "-Inf"},
+		{0xc000000000000000, 0x7fff, "NaN"},
+		{0x8000000000000000, 0x3fff, "1e+00"},
+		{0x8000000000000000, 0xbfff, "-1e+00"},
+		{0xcccccccccccccccd, 0x3ffb, "1e-01"},
+		{0xc90fdaa22168c235, 0x4000, "3.1415926535897932385e+00"},
+		{0xffffffffffffffff, 0x7ffe, "1.189731495357231765e+4932"},  // largest finite
+		{0x8000000000000000, 0x0001, "3.3621031431120935063e-4932"}, // smallest normal
+		{0x7fffffffffffffff, 0x0000, "3.362103143112093506e-4932"},  // largest denormal
+		{0x0000000000000001, 0x0000, "4e-4951"},                     // smallest denormal
+
+		// Pseudo-denormals, unnormals, pseudo-infinities, and
+		// pseudo-NaNs are invalid.
+		{0x8000000000000000, 0x0000, "NaN"},
+		{0xffffffffffffffff, 0x8000, "NaN"},
+		{0x4000000000000000, 0x3fff, "NaN"},
+		{0x0000000000000000, 0x3fff, "NaN"},
+		{0x0000000000000000, 0x7fff, "NaN"},
+		{0x4000000000000000, 0xffff, "NaN"},
+	} {
+		got := FormatFloat80(tt.mant, tt.signExp)
+		if got != tt.want {
+			t.Errorf("FormatFloat80(%#016x, %#04x): got %q; want %q", tt.mant, tt.signExp, got, tt.want)
+		}
+	}
+}
+
+func TestFormatFloat80Ref(t *testing.T) {
+	exps := []uint16{0, 1, 2, bias80 - 1, bias80, bias80 + 1, 1<<expBits80 - 3, 1<<expBits80 - 2}
+	for e := uint16(bias80 + mantBits80 - 8); e <= bias80+mantBits80+8; e++ {
+		exps = append(exps, e)
+	}
+	r := rand.New(rand.NewSource(1))
+	type f80 struct {
+		mant    uint64
+		signExp uint16
+	}
+	var cases []f80
+	for _, e := range exps {
+		for _, m := range []uint64{1 << 63, 1<<63 | 1, 1<<64 - 1, 1<<63 | r.Uint64()} {
+			if e == 0 {
+				// Use denormals rather than pseudo-denormals.
+				m &^= 1 << 63
+			}
+			cases = append(cases, f80{m, e})
+		}
+	}
+	n := 1000
+	if testing.Short() {
+		n = 100
+	}
+	for i := 0; i < n; i++ {
+		// Generate only normal numbers and denormals.
+		signExp := uint16(r.Intn(1<<16 - 1))
+		mant := r.Uint64() | 1<<63
+		if signExp&(1<<expBits80-1) == 0 {
+			mant &^= 1 << 63
+		}
+		cases = append(cases, f80{mant, signExp})
+	}
+	for _, c := range cases {
+		exp := int(c.signExp) & (1<<expBits80 - 1)
+		if exp == 1<<expBits80-1 || c.mant == 0 {
+			continue
+		}
+		// Convert to an implicit leading bit for refShortestString.
+		frac := new(big.Int).SetUint64(c.mant &^ (1 << 63))
+		want := refShortestString(c.signExp>>15 != 0, frac, exp, mantBits80-1, bias80)
+		got := FormatFloat80(c.mant, c.signExp)
+		if got != want {
+			t.Errorf("FormatFloat80(%#016x, %#04x): got %q; want %q", c.mant, c.signExp, got, want)
+		}
+	}
+}
+
+func TestPow5Mul64(t *testing.T) {
+	// Check the float64 tables (computed, with the ryu_small build tag)
+	// against their definitions.
+	for i := uint32(0); i < posTableSize64; i++ {
+		p := new(big.Int).Exp(big.NewInt(5), big.NewInt(int64(i)), nil)
+		want := new(big.Int).Set(p)
+		if shift := p.BitLen() - pow5NumBits64; shift > 0 {
+			want.Rsh(want, uint(shift))
+		} else {
+			want.Lsh(want, uint(-shift))
+		}
+		got := pow5Mul64(i)
+		if wordsToInt([]uint64{got.lo, got.hi}).Cmp(want) != 0 {
+			t.Errorf("pow5Mul64(%d): got %s; want %s", i, wordsToInt([]uint64{got.lo, got.hi}), want)
+		}
+	}
+	for i := uint32(0); i < negTableSize64; i++ {
+		p := new(big.Int).Exp(big.NewInt(5), big.NewInt(int64(i)), nil)
+		want := new(big.Int).Lsh(big.NewInt(1), uint(p.BitLen()-1+pow5InvNumBits64))
+		want.Quo(want, p)
+		want.Add(want, big.NewInt(1))
+		got := pow5InvMul64(i)
+		if wordsToInt([]uint64{got.lo, got.hi}).Cmp(want) != 0 {
+			t.Errorf("pow5InvMul64(%d): got %s; want %s", i, wordsToInt([]uint64{got.lo, got.hi}), want)
+		}
+	}
+}
+
+func TestPow5Generic(t *testing.T) {
+	// Check every power of 5 that the tables cover against the
+	// definitions of pow5Split64 and pow5InvSplit64, extended to
+	// pow5NumBits128 and pow5InvNumBits128 bits.
+	for i := uint32(0); i < uint32(len(pow5Split128)*pow5TableSize128); i++ {
+		p := new(big.Int).Exp(big.NewInt(5), big.NewInt(int64(i)), nil)
+		want := new(big.Int).Set(p)
+		if shift := p.BitLen() - pow5NumBits128; shift > 0 {
+			want.Rsh(want, uint(shift))
+		} else {
+			want.Lsh(want, uint(-shift))
+		}
+		if got := pow5Generic(i); wordsToInt(got[:]).Cmp(want) != 0 {
+			t.Errorf("pow5Generic(%d): got %s; want %s", i, wordsToInt(got[:]), want)
+		}
+		want.Lsh(big.NewInt(1), uint(p.BitLen()-1+pow5InvNumBits128))
+		want.Quo(want, p)
+		want.Add(want, big.NewInt(1))
+		if got := pow5InvGeneric(i); wordsToInt(got[:]).Cmp(want) != 0 {
+			t.Errorf("pow5InvGeneric(%d): got %s; want %s", i, wordsToInt(got[:]), want)
+		}
+	}
+}
+
+func TestLogGeneric(t *testing.T) {
+	// Keep track of 2^e and 5^e along with the next powers of 10
+	// above them.
+	p2, p5 := big.NewInt(1), big.NewInt(1)
+	next2, next5 := big.NewInt(10), big.NewInt(10)
+	var log10p2, log10p5 uint32
+	for e := int32(0); e <= 1<<15; e++ {
+		for next2.Cmp(p2) <= 0 {
+			next2.Mul(next2, big.NewInt(10))
+			log10p2++
+		}
+		for next5.Cmp(p5) <= 0 {
+			next5.Mul(next5, big.NewInt(10))
+			log10p5++
+		}
+		if got := log10Pow2Generic(e); got != log10p2 {
+			t.Fatalf("log10Pow2Generic(%d) = %d; want %d", e, got, log10p2)
+		}
+		if got := log10Pow5Generic(e); got != log10p5 {
+			t.Fatalf("log10Pow5Generic(%d) = %d; want %d", e, got, log10p5)
+		}
+		if got, want := pow5BitsGeneric(e), int32(p5.BitLen()); got != want {
+			t.Fatalf("pow5BitsGeneric(%d) = %d; want %d", e, got, want)
+		}
+		p2.Lsh(p2, 1)
+		p5.Mul(p5, big.NewInt(5))
+	}
+}
+
+func wordsToInt(w []uint64) *big.Int {
+	x := new(big.Int)
+	for i := len(w) - 1; i >= 0; i-- {
+		x.Lsh(x, 64)
+		x.Or(x, new(big.Int).SetUint64(w[i]))
+	}
+	return x
+}
+
+// refShortestString formats the finite nonzero value with the given sign,
+// mantissa bits, and biased exponent like FormatFloat32, using
+// verify.ShortestDecimal to find the digits.
+func refShortestString(neg bool, mant *big.Int, exp int, mantBits uint, bias int) string {
+	m2 := new(big.Int).Set(mant)
+	e2 := exp - bias - int(mantBits)
+	if exp == 0 {
+		e2++
+	} else {
+		m2.SetBit(m2, int(mantBits), 1)
+	}
+	digits, e10 := verify.ShortestDecimal(m2, e2, mant.Sign() == 0 && exp > 1)
+	s := digits.String()
+	e10 += len(s) - 1
+	if len(s) > 1 {
+		s = s[:1] + "." + s[1:]
+	}
+	if neg {
+		s = "-" + s
+	}
+	return fmt.Sprintf("%se%+03d", s, e10)
+}
+
 func TestFormatFloatRandom(t *testing.T) {
-	t.Skip("disabled because of Go bug: https://github.com/golang/go/issues/29491")
-	for i := 0; i < 1e6; i++ {
+	n := int(1e5)
+	if testing.Short() {
+		n = 1e4
+	}
+	for i := 0; i < n; i++ {
 		f := math.Float64frombits(rand.Uint64())
 
 		got32 := FormatFloat32(float32(f))
-		want32 := strconv.FormatFloat(f, 'e', -1, 32)
+		want32 := shortestString32(float32(f))
 		if got32 != want32 {
-			t.Fatalf("FormatFloat32(%g): got %q; want %q", f, got32, want32)
+			t.Fatalf("FormatFloat32(%g): got %q; want %q", float32(f), got32, want32)
 		}
 
 		got := FormatFloat64(f)
-		want := strconv.FormatFloat(f, 'e', -1, 64)
+		want := shortestString64(f)
 		if got != want {
 			t.Fatalf("FormatFloat64(%g): got %q; want %q", f, got, want)
 		}
 	}
 }
 
+func TestAppendFloats(t *testing.T) {
+	var fs64 []float64
+	var fs32 []float32
+	var want64, want32 []string
+	for i := 0; i < 1000; i++ {
+		f := math.Float64frombits(rand.Uint64())
+		fs64 = append(fs64, f)
+		fs32 = append(fs32, float32(f))
+		want64 = append(want64, FormatFloat64(f))
+		want32 = append(want32, FormatFloat32(float32(f)))
+	}
+	for _, n := range []int{0, 1, 2, len(fs64)} {
+		prefix := []byte("x=")
+		got := AppendFloat64s(prefix, fs64[:n], ' ')
+		want := "x=" + strings.Join(want64[:n], " ")
+		if string(got) != want {
+			t.Errorf("AppendFloat64s (n=%d): got %q; want %q", n, got, want)
+		}
+		got = AppendFloat32s(prefix, fs32[:n], ' ')
+		want = "x=" + strings.Join(want32[:n], " ")
+		if string(got) != want {
+			t.Errorf("AppendFloat32s (n=%d): got %q; want %q", n, got, want)
+		}
+	}
+}
+
 func TestDecimalLen(t *testing.T) {
 	for n := uint64(1); n < 1000; n++ {
 		testDecimalLen(t, n)
@@ -237,6 +1049,71 @@ func BenchmarkAppendFloat64(b *testing.B) {
 	}
 }
 
+func BenchmarkAppendFloat64s(b *testing.B) {
+	fs := make([]float64, 1000)
+	for i := range fs {
+		fs[i] = math.Float64frombits(rand.Uint64())
+	}
+	b.Run("batch", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			sinkb = AppendFloat64s(nil, fs, ',')
+		}
+	})
+	b.Run("loop", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			var buf []byte
+			for j, f := range fs {
+				if j > 0 {
+					buf = append(buf, ',')
+				}
+				buf = AppendFloat64(buf, f)
+			}
+			sinkb = buf
+		}
+	})
+}
+
+func BenchmarkAppendFloat32s(b *testing.B) {
+	fs := make([]float32, 1000)
+	for i := range fs {
+		fs[i] = math.Float32frombits(rand.Uint32())
+	}
+	b.Run("batch", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			sinkb = AppendFloat32s(nil, fs, ',')
+		}
+	})
+	b.Run("loop", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			var buf []byte
+			for j, f := range fs {
+				if j > 0 {
+					buf = append(buf, ',')
+				}
+				buf = AppendFloat32(buf, f)
+			}
+			sinkb = buf
+		}
+	})
+}
+
+func BenchmarkAppendFloat128(b *testing.B) {
+	for _, f := range [][2]uint64{
+		{0x3fff000000000000, 0},                  // 1
+		{0x3ffb999999999999, 0x999999999999999a}, // 0.1
+		{0x4000921fb54442d1, 0x8469898cc51701b8}, // pi
+		{0x0000000000000000, 1},                  // smallest subnormal
+	} {
+		b.Run(FormatFloat128(f[0], f[1]), func(b *testing.B) {
+			var buf []byte
+			for i := 0; i < b.N; i++ {
+				buf = AppendFloat128(buf[:0], f[0], f[1])
+			}
+			sinkb = buf
+		})
+	}
+}
+
 func BenchmarkStrconvAppendFloat64(b *testing.B) {
 	for _, f := range append(benchCases, benchCases64...) {
 		b.Run(FormatFloat64(f), func(b *testing.B) {
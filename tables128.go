@@ -0,0 +1,352 @@
+// Code generated by running "go generate". DO NOT EDIT.
+
+// Copyright 2018 Ulf Adams
+// Modifications copyright 2019 Caleb Spare
+//
+// The contents of this file may be used under the terms of the Apache License,
+// Version 2.0.
+//
+//    (See accompanying file LICENSE or copy at
+//     http://www.apache.org/licenses/LICENSE-2.0)
+//
+// Unless required by applicable law or agreed to in writing, this software
+// is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.
+//
+// The code in this file is part of a Go translation of the C code written by
+// Ulf Adams which may be found at https://github.com/ulfjack/ryu. That source
+// code is licensed under Apache 2.0 and this code is derivative work thereof.
+
+package ryu
+
+const pow5TableSize128 = 56
+
+var pow5Table128 = [...]uint128{
+	{1, 0},
+	{5, 0},
+	{25, 0},
+	{125, 0},
+	{625, 0},
+	{3125, 0},
+	{15625, 0},
+	{78125, 0},
+	{390625, 0},
+	{1953125, 0},
+	{9765625, 0},
+	{48828125, 0},
+	{244140625, 0},
+	{1220703125, 0},
+	{6103515625, 0},
+	{30517578125, 0},
+	{152587890625, 0},
+	{762939453125, 0},
+	{3814697265625, 0},
+	{19073486328125, 0},
+	{95367431640625, 0},
+	{476837158203125, 0},
+	{2384185791015625, 0},
+	{11920928955078125, 0},
+	{59604644775390625, 0},
+	{298023223876953125, 0},
+	{1490116119384765625, 0},
+	{7450580596923828125, 0},
+	{359414837200037393, 2},
+	{1797074186000186965, 10},
+	{8985370930000934825, 50},
+	{8033366502585570893, 252},
+	{3273344365508751233, 1262},
+	{16366721827543756165, 6310},
+	{8046632842880574361, 31554},
+	{3339676066983768573, 157772},
+	{16698380334918842865, 788860},
+	{9704925379756007861, 3944304},
+	{11631138751360936073, 19721522},
+	{2815461535676025517, 98607613},
+	{14077307678380127585, 493038065},
+	{15046306170771983077, 2465190328},
+	{1444554559021708921, 12325951644},
+	{7222772795108544605, 61629758220},
+	{17667119901833171409, 308148791101},
+	{14548623214327650581, 1540743955509},
+	{17402883850509598057, 7703719777548},
+	{13227442957709783821, 38518598887744},
+	{10796982567420264257, 192592994438723},
+	{17091424689682218053, 962964972193617},
+	{11670147153572883801, 4814824860968089},
+	{3010503546735764157, 24074124304840448},
+	{15052517733678820785, 120370621524202240},
+	{1475612373555897461, 601853107621011204},
+	{7378061867779487305, 3009265538105056020},
+	{18443565265187884909, 15046327690525280101},
+}
+
+const pow5NumBits128 = 249
+
+var pow5Split128 = [...][4]uint64{
+	{0, 0, 0, 72057594037927936},
+	{0, 5206161169240293376, 4575641699882439235, 73468396926392969},
+	{3360510775605221349, 6983200512169538081, 4325643253124434363, 74906821675075173},
+	{11917660854915489451, 9652941469841108803, 946308467778435600, 76373409087490117},
+	{1994853395185689235, 16102657350889591545, 6847013871814915412, 77868710555449746},
+	{958415760277438274, 15059347134713823592, 7329070255463483331, 79393288266368765},
+	{2065144883315240188, 7145278325844925976, 14718454754511147343, 80947715414629833},
+	{8980391188862868935, 13709057401304208685, 8230434828742694591, 82532576417087045},
+	{432148644612782575, 7960151582448466064, 12056089168559840552, 84148467132788711},
+	{484109300864744403, 15010663910730448582, 16824949663447227068, 85795995087002057},
+	{14793711725276144220, 16494403799991899904, 10145107106505865967, 87475779699624060},
+	{15427548291869817042, 12330588654550505203, 13980791795114552342, 89188452518064298},
+	{9979404135116626552, 13477446383271537499, 14459862802511591337, 90934657454687378},
+	{12385121150303452775, 9097130814231585614, 6523855782339765207, 92715051028904201},
+	{1822931022538209743, 16062974719797586441, 3619180286173516788, 94530302614003091},
+	{12318611738248470829, 13330752208259324507, 10986694768744162601, 96381094688813589},
+	{13684493829640282333, 7674802078297225834, 15208116197624593182, 98268123094297527},
+	{5408877057066295332, 6470124174091971006, 15112713923117703147, 100192097295163851},
+	{11407083166564425062, 18189998238742408185, 4337638702446708282, 102153740646605557},
+	{4112405898036935485, 924624216579956435, 14251108172073737125, 104153790666259019},
+	{16996739107011444789, 10015944118339042475, 2395188869672266257, 106192999311487969},
+	{4588314690421337879, 5339991768263654604, 15441007590670620066, 108272133262096356},
+	{2286159977890359825, 14329706763185060248, 5980012964059367667, 110391974208576409},
+	{9654767503237031099, 11293544302844823188, 11739932712678287805, 112553319146000238},
+	{11362964448496095896, 7990659682315657680, 251480263940996374, 114756980673665505},
+	{1423410421096377129, 14274395557581462179, 16553482793602208894, 117003787300607788},
+	{2070444190619093137, 11517140404712147401, 11657844572835578076, 119294583757094535},
+	{7648316884775828921, 15264332483297977688, 247182277434709002, 121630231312217685},
+	{17410896758132241352, 10923914482914417070, 13976383996795783649, 124011608097704390},
+	{9542674537907272703, 3079432708831728956, 14235189590642919676, 126439609438067572},
+	{10364666969937261816, 8464573184892924210, 12758646866025101190, 128915148187220428},
+	{14720354822146013883, 11480204489231511423, 7449876034836187038, 131439155071681461},
+	{1692907053653558553, 17835392458598425233, 1754856712536736598, 134012579040499057},
+	{5620591334531458755, 11361776175667106627, 13350215315297937856, 136636387622027174},
+	{17455759733928092601, 10362573084069962561, 11246018728801810510, 139311567287686283},
+	{2465404073814044982, 17694822665274381860, 1509954037718722697, 142039123822846312},
+	{2152236053329638369, 11202280800589637091, 16388426812920420176, 72410041352485523},
+	{17319024055671609028, 10944982848661280484, 2457150158022562661, 73827744744583080},
+	{17511219308535248024, 5122059497846768077, 2089605804219668451, 75273205100637900},
+	{10082673333144031533, 14429008783411894887, 12842832230171903890, 76746965869337783},
+	{16196653406315961184, 10260180891682904501, 10537411930446752461, 78249581139456266},
+	{15084422041749743389, 234835370106753111, 16662517110286225617, 79781615848172976},
+	{8199644021067702606, 3787318116274991885, 7438130039325743106, 81343645993472659},
+	{12039493937039359765, 9773822153580393709, 5945428874398357806, 82936258850702722},
+	{984543865091303961, 7975107621689454830, 6556665988501773347, 84560053193370726},
+	{9633317878125234244, 16099592426808915028, 9706674539190598200, 86215639518264828},
+	{6860695058870476186, 4471839111886709592, 7828342285492709568, 87903640274981819},
+	{14583324717644598331, 4496120889473451238, 5290040788305728466, 89624690099949049},
+	{18093669366515003715, 12879506572606942994, 18005739787089675377, 91379436055028227},
+	{17997493966862379937, 14646222655265145582, 10265023312844161858, 93168537870790806},
+	{12283848109039722318, 11290258077250314935, 9878160025624946825, 94992668194556404},
+	{8087752761883078164, 5262596608437575693, 11093553063763274413, 96852512843287537},
+	{15027787746776840781, 12250273651168257752, 9290470558712181914, 98748771061435726},
+	{15003915578366724489, 2937334162439764327, 5404085603526796602, 100682155783835929},
+	{5225610465224746757, 14932114897406142027, 2774647558180708010, 102653393903748137},
+	{17112957703385190360, 12069082008339002412, 3901112447086388439, 104663226546146909},
+	{4062324464323300238, 3992768146772240329, 15757196565593695724, 106712409346361594},
+	{5525364615810306701, 11855206026704935156, 11344868740897365300, 108801712734172003},
+	{9274143661888462646, 4478365862348432381, 18010077872551661771, 110931922223466333},
+	{12604141221930060148, 8930937759942591500, 9382183116147201338, 113103838707570263},
+	{14513929377491886653, 1410646149696279084, 587092196850797612, 115318278760358235},
+	{2226851524999454362, 7717102471110805679, 7187441550995571734, 117576074943260147},
+	{5527526061344932763, 2347100676188369132, 16976241418824030445, 119878076118278875},
+	{6088479778147221611, 17669593130014777580, 10991124207197663546, 122225147767136307},
+	{11107734086759692041, 3391795220306863431, 17233960908859089158, 124618172316667879},
+	{7913172514655155198, 17726879005381242552, 641069866244011540, 127058049470587962},
+	{12596991768458713949, 15714785522479904446, 6035972567136116512, 129545696547750811},
+	{16901996933781815980, 4275085211437148707, 14091642539965169063, 132082048827034281},
+	{7524574627987869240, 15661204384239316051, 2444526454225712267, 134668059898975949},
+	{8199251625090479942, 6803282222165044067, 16064817666437851504, 137304702024293857},
+	{4453256673338111920, 15269922543084434181, 3139961729834750852, 139992966499426682},
+	{15841763546372731299, 3013174075437671812, 4383755396295695606, 142733864029230733},
+	{9771896230907310329, 4900659362437687569, 12386126719044266361, 72764212553486967},
+	{9420455527449565190, 1859606122611023693, 6555040298902684281, 74188850200884818},
+	{5146105983135678095, 2287300449992174951, 4325371679080264751, 75641380576797959},
+	{11019359372592553360, 8422686425957443718, 7175176077944048210, 77122349788024458},
+	{11005742969399620716, 4132174559240043701, 9372258443096612118, 78632314633490790},
+	{8887589641394725840, 8029899502466543662, 14582206497241572853, 80171842813591127},
+	{360247523705545899, 12568341805293354211, 14653258284762517866, 81741513143625247},
+	{12314272731984275834, 4740745023227177044, 6141631472368337539, 83341915771415304},
+	{441052047733984759, 7940090120939869826, 11750200619921094248, 84973652399183278},
+	{3436657868127012749, 9187006432149937667, 16389726097323041290, 86637336509772529},
+	{13490220260784534044, 15339072891382896702, 8846102360835316895, 88333593597298497},
+	{4125672032094859833, 158347675704003277, 10592598512749774447, 90063061402315272},
+	{12189928252974395775, 2386931199439295891, 7009030566469913276, 91826390151586454},
+	{9256479608339282969, 2844900158963599229, 11148388908923225596, 93624242802550437},
+	{11584393507658707408, 2863659090805147914, 9873421561981063551, 95457295292572042},
+	{13984297296943171390, 1931468383973130608, 12905719743235082319, 97326236793074198},
+	{5837045222254987499, 10213498696735864176, 14893951506257020749, 99231769968645227},
+}
+
+const pow5InvNumBits128 = 249
+
+var pow5InvSplit128 = [...][4]uint64{
+	{0, 0, 0, 144115188075855872},
+	{1573859546583440065, 2691002611772552616, 6763753280790178510, 141347765182270746},
+	{12960290449513840412, 12345512957918226762, 18057899791198622765, 138633484706040742},
+	{7615871757716765416, 9507132263365501332, 4879801712092008245, 135971326161092377},
+	{7869961150745287587, 5804035291554591636, 8883897266325833928, 133360288657597085},
+	{2942118023529634767, 15128191429820565086, 10638459445243230718, 130799390525667397},
+	{14188759758411913794, 5362791266439207815, 8068821289119264054, 128287668946279217},
+	{7183196927902545212, 1952291723540117099, 12075928209936341512, 125824179589281448},
+	{5672588001402349748, 17892323620748423487, 9874578446960390364, 123407996258356868},
+	{4442590541217566325, 4558254706293456445, 10343828952663182727, 121038210542800766},
+	{3005560928406962566, 2082271027139057888, 13961184524927245081, 118713931475986426},
+	{13299058168408384786, 17834349496131278595, 9029906103900731664, 116434285200389047},
+	{5414878118283973035, 13079825470227392078, 17897304791683760280, 114198414639042157},
+	{14609755883382484834, 14991702445765844156, 3269802549772755411, 112005479173303009},
+	{15967774957605076027, 2511532636717499923, 16221038267832563171, 109854654326805788},
+	{9269330061621627145, 3332501053426257392, 16223281189403734630, 107745131455483836},
+	{16739559299223642282, 1873986623300664530, 6546709159471442872, 105676117443544318},
+	{17116435360051202055, 1359075105581853924, 2038341371621886470, 103646834405281051},
+	{17144715798009627550, 3201623802661132408, 9757551605154622431, 101656519392613377},
+	{17580479792687825857, 6546633380567327312, 15099972427870912398, 99704424108241124},
+	{9726477118325522902, 14578369026754005435, 11728055595254428803, 97789814624307808},
+	{134593949518343635, 5715151379816901985, 1660163707976377376, 95911971106466306},
+	{5515914027713859358, 7124354893273815720, 5548463282858794077, 94070187543243255},
+	{6188403395862945512, 5681264392632320838, 15417410852121406654, 92263771480600430},
+	{15908890877468271457, 10398888261125597540, 4817794962769172309, 90492043761593298},
+	{1413077535082201005, 12675058125384151580, 7731426132303759597, 88754338271028867},
+	{1486733163972670293, 11369385300195092554, 11610016711694864110, 87050001685026843},
+	{8788596583757589684, 3978580923851924802, 9255162428306775812, 85378393225389919},
+	{7203518319660962120, 15044736224407683725, 2488132019818199792, 83738884418690858},
+	{4004175967662388707, 18236988667757575407, 15613100370957482671, 82130858859985791},
+	{18371903370586036463, 53497579022921640, 16465963977267203307, 80553711981064899},
+	{10170778323887491315, 1999668801648976001, 10209763593579456445, 79006850823153334},
+	{17108131712433974546, 16825784443029944237, 2078700786753338945, 77489693813976938},
+	{17221789422665858532, 12145427517550446164, 5391414622238668005, 76001670549108934},
+	{4859588996898795878, 1715798948121313204, 3950858167455137171, 74542221577515387},
+	{13513469241795711526, 631367850494860526, 10517278915021816160, 73110798191218799},
+	{11757513142672073111, 2581974932255022228, 17498959383193606459, 143413724438001539},
+	{14524355192525042817, 5640643347559376447, 1309659274756813016, 140659771648132296},
+	{2765095348461978538, 11021111021896007722, 3224303603779962366, 137958702611185230},
+	{12373410389187981037, 13679193545685856195, 11644609038462631561, 135309501808182158},
+	{12813176257562780151, 3754199046160268020, 9954691079802960722, 132711173221007413},
+	{17557452279667723458, 3237799193992485824, 17893947919029030695, 130162739957935629},
+	{14634200999559435155, 4123869946105211004, 6955301747350769239, 127663243886350468},
+	{2185352760627740240, 2864813346878886844, 13049218671329690184, 125211745272516185},
+	{6143438674322183002, 10464733336980678750, 6982925169933978309, 122807322428266620},
+	{1099509117817174576, 10202656147550524081, 754997032816608484, 120449071364478757},
+	{2410631293559367023, 17407273750261453804, 15307291918933463037, 118136105451200587},
+	{12224968375134586697, 1664436604907828062, 11506086230137787358, 115867555084305488},
+	{3495926216898000888, 18392536965197424288, 10992889188570643156, 113642567358547782},
+	{8744506286256259680, 3966568369496879937, 18342264969761820037, 111460305746896569},
+	{7689600520560455039, 5254331190877624630, 9628558080573245556, 109319949786027263},
+	{11862637625618819436, 3456120362318976488, 14690471063106001082, 107220694767852583},
+	{5697330450030126444, 12424082405392918899, 358204170751754904, 105161751436977040},
+	{11257457505097373622, 15373192700214208870, 671619062372033814, 103142345693961148},
+	{16850355018477166700, 1913910419361963966, 4550257919755970531, 101161718304283822},
+	{9670835567561997011, 10584031339132130638, 3060560222974851757, 99219124612893520},
+	{7698686577353054710, 11689292838639130817, 11806331021588878241, 97313834264240819},
+	{12233569599615692137, 3347791226108469959, 10333904326094451110, 95445130927687169},
+	{13049400362825383933, 17142621313007799680, 3790542585289224168, 93612312028186576},
+	{12430457242474442072, 5625077542189557960, 14765055286236672238, 91814688482138969},
+	{4759444137752473128, 2230562561567025078, 4954443037339580076, 90051584438315940},
+	{7246913525170274758, 8910297835195760709, 4015904029508858381, 88322337023761438},
+	{12854430245836432067, 8135139748065431455, 11548083631386317976, 86626296094571907},
+	{4848827254502687803, 4789491250196085625, 3988192420450664125, 84962823991462151},
+	{7435538409611286684, 904061756819742353, 14598026519493048444, 83331295300025028},
+	{11042616160352530997, 8948390828345326218, 10052651191118271927, 81731096615594853},
+	{11059348291563778943, 11696515766184685544, 3783210511290897367, 80161626312626082},
+	{7020010856491885826, 5025093219346041680, 8960210401638911765, 78622294318500592},
+	{17732844474490699984, 7820866704994446502, 6088373186798844243, 77112521891678506},
+	{688278527545590501, 3045610706602776618, 8684243536999567610, 75631741404109150},
+	{2734573255120657297, 3903146411440697663, 9470794821691856713, 74179396127820347},
+	{15996457521023071259, 4776627823451271680, 12394856457265744744, 72754940025605801},
+	{13492065758834518331, 7390517611012222399, 1630485387832860230, 142715675091463768},
+	{13665021627282055864, 9897834675523659302, 17907668136755296849, 139975126841173266},
+	{9603773719399446181, 10771916301484339398, 10672699855989487527, 137287204938390542},
+	{3630218541553511265, 8139010004241080614, 2876479648932814543, 134650898807055963},
+	{8318835909686377084, 9525369258927993371, 2796120270400437057, 132065217277054270},
+	{11190003059043290163, 12424345635599592110, 12539346395388933763, 129529188211565064},
+	{8701968833973242276, 820569587086330727, 2315591597351480110, 127041858141569228},
+	{5115113890115690487, 16906305245394587826, 9899749468931071388, 124602291907373862},
+	{15543535488939245974, 10945189844466391399, 3553863472349432246, 122209572307020975},
+	{7709257252608325038, 1191832167690640880, 15077137020234258537, 119862799751447719},
+	{7541333244210021737, 9790054727902174575, 5160944773155322014, 117561091926268545},
+	{12297384708782857832, 1281328873123467374, 4827925254630475769, 115303583460052092},
+	{13243237906232367265, 15873887428139547641, 3607993172301799599, 113089425598968120},
+	{11384616453739611114, 15184114243769211033, 13148448124803481057, 110917785887682141},
+	{17727970963596660683, 1196965221832671990, 14537830463956404138, 108787847856377790},
+	{17241367586707330931, 8880584684128262874, 11173506540726547818, 106698810713789254},
+	{7184427196661305643, 14332510582433188173, 14230167953789677901, 104649889046128358},
+	{11627596930065028749, 12678231676030021774, 12994762426130629002, 102640312521793111},
+}
+var pow5Errors128 = [...]uint64{
+	0x0000000000000000, 0x0000000000000000, 0x0000000000000000, 0x9555596400000000,
+	0x65a6569525565555, 0x4415551445449655, 0x5105015504144541, 0x65a69969a6965964,
+	0x5054955969959656, 0x5105154515554145, 0x4055511051591555, 0x5500514455550115,
+	0x0041140014145515, 0x1005440545511051, 0x0014405450411004, 0x0414440010500000,
+	0x0044000440010040, 0x5551155000004001, 0x4554555454544114, 0x5150045544005441,
+	0x0001111400054501, 0x6550955555554554, 0x1504159645559559, 0x4105055141454545,
+	0x1411541410405454, 0x0415555044545555, 0x0014154115405550, 0x1540055040411445,
+	0x0000000500000000, 0x5644000000000000, 0x1155555591596555, 0x0410440054569565,
+	0x5145100010010005, 0x0555041405500150, 0x4141450455140450, 0x0000000144000140,
+	0x5114004001105410, 0x4444100404005504, 0x0414014410001015, 0x5145055155555015,
+	0x0141041444445540, 0x0000100451541414, 0x4105041104155550, 0x0500501150451145,
+	0x1001050000004114, 0x5551504400141045, 0x5110545410151454, 0x0100001400004040,
+	0x5040010111040000, 0x0140000150541100, 0x4400140400104110, 0x5011014405545004,
+	0x0000000044155440, 0x0000000010000000, 0x1100401444440001, 0x0040401010055111,
+	0x5155155551405454, 0x0444440015514411, 0x0054505054014101, 0x0451015441115511,
+	0x1541411401140551, 0x4155104514445110, 0x4141145450145515, 0x5451445055155050,
+	0x4400515554110054, 0x5111145104501151, 0x565a655455500501, 0x5565555555525955,
+	0x0550511500405695, 0x4415504051054544, 0x6555595965555554, 0x0100915915555655,
+	0x5540001510001001, 0x5450051414000544, 0x1405010555555551, 0x5555515555644155,
+	0x5555055595496555, 0x5451045004415000, 0x5450510144040144, 0x5554155555556455,
+	0x5051555495415555, 0x5555554555555545, 0x0000000010005455, 0x4000005000040000,
+	0x5565555555555954, 0x5554559555555505, 0x9645545495552555, 0x4000400055955564,
+	0x0040000000000001, 0x4004100100000000, 0x5540040440000411, 0x4565555955545644,
+	0x1140659549651556, 0x0100000410010000, 0x5555515400004001, 0x5955545555155255,
+	0x5151055545505556, 0x5051454510554515, 0x0501500050415554, 0x5044154005441005,
+	0x1455445450550455, 0x0010144055144545, 0x0000401100000004, 0x1050145050000010,
+	0x0415004554011540, 0x1000510100151150, 0x0100040400001144, 0x0000000000000000,
+	0x0550004400000100, 0x0151145041451151, 0x0000400400005450, 0x0000100044010004,
+	0x0100054100050040, 0x0504400005410010, 0x4011410445500105, 0x0000404000144411,
+	0x0101504404500000, 0x0000005044400400, 0x0000000014000100, 0x0404440414000000,
+	0x5554100410000140, 0x4555455544505555, 0x5454105055455455, 0x0115454155454015,
+	0x4404110000045100, 0x4400001100101501, 0x6596955956966a94, 0x0040655955665965,
+	0x5554144400100155, 0xa549495401011041, 0x5596555565955555, 0x5569965959549555,
+	0x969565a655555456, 0x0000001000000000, 0x0000000040000140, 0x0000040100000000,
+	0x1415454400000000, 0x5410415411454114, 0x0400040104000154, 0x0504045000000411,
+	0x0000001000000010, 0x5554000000001040, 0x5549155551556595, 0x1455541055515555,
+	0x0510555454554541, 0x9555555555540455, 0x6455456555556465, 0x4524565555654514,
+	0x5554655255559545, 0x9555455441155556, 0x0000000051515555, 0x0010005040000550,
+	0x5044044040000000, 0x1045040440010500, 0x0000400000040000, 0x0000000000000000,
+}
+var pow5InvErrors128 = [...]uint64{
+	0x66996aaa6969aa58, 0x5554a96aaa956696, 0x5555555555555555, 0x56a99a9455555555,
+	0x966965aa6aa99995, 0x5556556666a4596a, 0x9596966965566555, 0xaaa5669a6a6aa568,
+	0x6958655596aa9aa6, 0x5a6a5559a5959965, 0xa5a9a99856695559, 0xa6aaa5655aaaa65a,
+	0x669969655564aa6a, 0x5a96a55555a55555, 0x665965aa95999694, 0x955456a5aaa69665,
+	0x55a95659a555955a, 0x96aaa6a85965555a, 0xa6aa69a59a6aaaaa, 0x6a66aaaa6a985aad,
+	0xaaada99aaaa6aaaa, 0x5555555555555564, 0xa5585555555555a5, 0x696aa65655555565,
+	0x9a9aaaa899a69a55, 0xa6aa6a6aaaaaaaa6, 0x6996a95699589aa9, 0xa6a565959aa99955,
+	0xa9a5a9a956999594, 0xaaa89aa59aa56955, 0x9baa6aaaaaaaa69a, 0x65555654aa9aa5aa,
+	0x65555595555aa559, 0x99aa95a65954555a, 0x9a55669a596a5a9a, 0x5555555569555554,
+	0xa9a4555555555555, 0xaa69aa6a6699aaaa, 0x9666a56495aaa9a6, 0x9a6a99aa555a9999,
+	0xa656a55659645996, 0x6a9a66a6aaa9a5aa, 0x5555555555555554, 0x6aa8555555655555,
+	0xaaaaa9aaeaaa6aaa, 0xaaaa5a6ca69aaeaa, 0xaaaaaaaaaaaaaaae, 0x5555955566546aaa,
+	0x5555559955595555, 0xa9aaa66aaaaa9aa8, 0xab68aaaa9966a99a, 0xb9aa6ab69aaaa6aa,
+	0xa9aadaa8aa96a9aa, 0xaa6aaaa699aaaa9a, 0x566995555568a6aa, 0x55555a65559a5a66,
+	0x99aa6a9aa999a654, 0x9aa86a5696a999aa, 0xbaeaaaaaaaefaeba, 0xaaaaaaaca9aaeaae,
+	0xeabeba9aaeb6baea, 0x659559959554aaba, 0x6565565a55566599, 0x6aa956aa9a6a9a94,
+	0x9994aaa956a9a996, 0x65699969a5aa565a, 0x9a9a955865a5969a, 0xa56a666a965956a6,
+	0xa69a5a66a9545965, 0x6595556599a9a599, 0x95569555556a6964, 0x5a94555599595555,
+	0x5a65aaa9a9966a99, 0x59555a94a9696aa5, 0x655659669a556655, 0x5555555695545555,
+	0x5555555569655555, 0x6a9955a9a9555694, 0x95a45aaa5a99a69a, 0x5566a66659a596aa,
+	0xaa5aa9986a59a5aa, 0x66aa6a999aa6aaa9, 0x5555555555549aaa, 0x5555555555555555,
+	0xa656565a65955558, 0x6a6859a599995955, 0xaa6aa6eaaaa6aaaa, 0x9aa9a9a896aaaa9a,
+	0x6aa65aaeaaaa6a6a, 0x56a5555566a4aa6a, 0x559955a595955555, 0x55595565595655a4,
+	0x55545a65599a5969, 0x5669556656556699, 0x5956555456555556, 0x9a55565556555956,
+	0x555965555554a555, 0x565a555996656655, 0x59aa9aaaa59a9a94, 0xa9585a569965aa5a,
+	0x9656a65a95aaa9aa, 0x65aaa968669a6aaa, 0xa9a699a66566aa5a, 0x66a9665565646a9a,
+	0x669a6959a55a95aa, 0xaaaaaba96aaa6aa8, 0x6aa4aaeaaaaaaaaa, 0xaaaaa96a9a59a696,
+	0x9aaa9aa8a5a55655, 0xaa659a9a9aaa9aaa, 0x6a6569566a58a9aa, 0x65565a5595666a65,
+	0xaaaa9a9aaaaaaa58, 0xeea86aaa9aa6aaa9, 0xbaebbabaaaaaaaaa, 0x56959654a6aaaaae,
+	0x5566659565556a99, 0xebabbebaefbc5a56, 0xabaafaaeaaaabeaa, 0x956a666569959a68,
+	0x69686aaaa9a5a69a, 0x5a955955665a6959, 0x656555545556a55a, 0x55655a95a5559965,
+	0xa596659569545655, 0x9995565a55655556, 0x66aaa65a59a9aaa8, 0x59a46a6a9a66aa96,
+	0x9555655955665995, 0x6559995465a69995, 0x555566a5a59a5555, 0x5a9a9599aaa86a55,
+	0x65a65a6aaaa5a656, 0xaa5a699aa9a99698, 0x9aa4a9aaaaa6aaa5, 0x556a9559a559a99a,
+	0x9a696aa855aa996a, 0x9aaa5aa5a65a56a6, 0x6a669969a5546569, 0x9a99aa9a65959969,
+	0x966a66aa9aa9a9a4, 0xaa54a96aaaaa6aaa, 0xaaa5565a996aa56a, 0x569996949aa9aa55,
+	0x96a95a55565a56a5, 0xaaa5a6666654569a, 0x6669a595aa5556a6, 0xa6595966569a6594,
+	0x5564a569565a6996, 0x5565a569a5a59956, 0x9aa9ada895599999, 0xa6aaaaaea69a59aa,
+	0x559555595564aaaa, 0x5a55555655555556, 0x9665a9655955a698, 0x00005959a6aaa6aa,
+}
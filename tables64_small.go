@@ -0,0 +1,74 @@
+// Code generated by running "go generate". DO NOT EDIT.
+
+// Copyright 2018 Ulf Adams
+// Modifications copyright 2019 Caleb Spare
+//
+// The contents of this file may be used under the terms of the Apache License,
+// Version 2.0.
+//
+//    (See accompanying file LICENSE or copy at
+//     http://www.apache.org/licenses/LICENSE-2.0)
+//
+// Unless required by applicable law or agreed to in writing, this software
+// is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.
+//
+// The code in this file is part of a Go translation of the C code written by
+// Ulf Adams which may be found at https://github.com/ulfjack/ryu. That source
+// code is licensed under Apache 2.0 and this code is derivative work thereof.
+
+//go:build ryu_small
+
+package ryu
+
+const pow5TableSize64 = 26
+
+var pow5Table64 = [...]uint64{
+	1, 5, 25, 125,
+	625, 3125, 15625, 78125,
+	390625, 1953125, 9765625, 48828125,
+	244140625, 1220703125, 6103515625, 30517578125,
+	152587890625, 762939453125, 3814697265625, 19073486328125,
+	95367431640625, 476837158203125, 2384185791015625, 11920928955078125,
+	59604644775390625, 298023223876953125,
+}
+var pow5Split64Small = [...]uint128{
+	{0, 72057594037927936},
+	{10376293541461622784, 93132257461547851},
+	{15052517733678820785, 120370621524202240},
+	{6258995034005762182, 77787690973264271},
+	{14893927168346708332, 100538234169297439},
+	{4272820386026678563, 129942622070561240},
+	{7330497575943398595, 83973451344588609},
+	{18377130505971182927, 108533142064701048},
+	{10038208235822497557, 140275798336537794},
+	{7017903361312433648, 90651109995611182},
+	{6366496589810271835, 117163813585596168},
+	{9264989777501460624, 75715339914673581},
+	{17074144231291089770, 97859783203563123},
+}
+var pow5InvSplit64Small = [...]uint128{
+	{0, 288230376151711744},
+	{7661987648932456966, 223007451985306231},
+	{12652048002903177472, 172543658669764094},
+	{5522544058086115565, 266998379490113760},
+	{3181575136763469021, 206579990246952687},
+	{4551508647133041039, 159833525776178802},
+	{1116074521063664380, 247330401473104534},
+	{17400360011128145021, 191362629322552438},
+	{9297997190148906105, 148059663038321393},
+	{11720143854957885428, 229111231347799689},
+	{15401709288678291154, 177266229209635622},
+	{3003071137298187332, 274306203439684434},
+	{17516772882021341107, 212234145163966538},
+}
+var pow5Offsets64 = [...]uint32{
+	0x00000000, 0x00000000, 0x00000000, 0x00000000, 0x40000000, 0x01111455, 0x51405055, 0x51451515,
+	0x55545045, 0x00414115, 0x00004000, 0x51114000, 0x14440555, 0x00000000, 0x00000000, 0x51544040,
+	0x00000000, 0x00000010, 0x55555555, 0x55545555, 0x00000555,
+}
+var pow5InvOffsets64 = [...]uint32{
+	0xa6a5a958, 0xaa4a9a69, 0x9aaaaa9a, 0x5aa66866, 0x45a5a565, 0x55555559, 0x55545555, 0x55555555,
+	0xaaaaa585, 0x54a5a5a6, 0x555a5595, 0xaaaa8555, 0xa6baeaae, 0x55556554, 0x6a455565, 0xaa9aaaaa,
+	0x969594a6, 0x45556565, 0x00000069,
+}
@@ -0,0 +1,274 @@
+// Copyright 2019 Caleb Spare
+//
+// The contents of this file may be used under the terms of the Apache License,
+// Version 2.0.
+//
+//    (See accompanying file LICENSE or copy at
+//     http://www.apache.org/licenses/LICENSE-2.0)
+//
+// Unless required by applicable law or agreed to in writing, this software
+// is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.
+
+// Package verify is a slow but simple reference implementation of the
+// shortest decimal representation of floating-point numbers, for testing
+// ryu and other formatters.
+//
+// It computes the representation with exact big.Int arithmetic on the
+// rounding interval of a float and does not depend on strconv's formatting,
+// which has had bugs in this area (https://golang.org/issue/29491).
+package verify
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"math/big"
+	"strconv"
+	"strings"
+)
+
+// ShortestDecimal64 returns the shortest decimal representation of the
+// absolute value of f, digits * 10^exp, in the same form as ryu.Decimal64:
+// among the decimals with the fewest digits that parse back to f, it is the
+// one closest to f, with ties going to even digits. Trailing zeros are
+// removed from digits. If f is zero, infinite, or NaN, digits and exp are
+// zero.
+func ShortestDecimal64(f float64) (digits uint64, exp int) {
+	if f == 0 || math.IsInf(f, 0) || math.IsNaN(f) {
+		return 0, 0
+	}
+	d, exp := shortest(math.Float64bits(f), 52, 11, 1023)
+	return d.Uint64(), exp
+}
+
+// ShortestDecimal32 is like ShortestDecimal64 for float32s.
+func ShortestDecimal32(f float32) (digits uint32, exp int) {
+	if f == 0 || math.IsInf(float64(f), 0) || math.IsNaN(float64(f)) {
+		return 0, 0
+	}
+	d, exp := shortest(uint64(math.Float32bits(f)), 23, 8, 127)
+	return uint32(d.Uint64()), exp
+}
+
+// shortest returns the shortest decimal for the finite, nonzero IEEE 754
+// float with the given bits, numbers of mantissa and exponent bits, and
+// exponent bias.
+func shortest(bits uint64, mantBits, expBits uint, bias int) (*big.Int, int) {
+	mant := bits & (1<<mantBits - 1)
+	exp := int(bits>>mantBits) & (1<<expBits - 1)
+	m2 := new(big.Int).SetUint64(mant)
+	e2 := exp - bias - int(mantBits)
+	if exp == 0 {
+		e2++
+	} else {
+		m2.SetBit(m2, int(mantBits), 1)
+	}
+	return ShortestDecimal(m2, e2, mant == 0 && exp > 1)
+}
+
+// ShortestDecimal returns the shortest decimal representation,
+// digits * 10^exp, of the binary floating-point value m2 * 2^e2 (m2 > 0) of
+// any format. That is:
+//
+//   - it lies in the rounding interval of the value: halfway to the next
+//     higher float and halfway to the next lower float, where the gap to
+//     the next lower float is half the usual size if lowerHalfGap is true
+//     (at a power of 2, where the exponent changes), and the interval is
+//     closed if m2 is even (round half to even);
+//   - 10^exp is the largest power of 10 of which some multiple lies in
+//     the interval;
+//   - among such multiples, it is the closest to the value, with ties
+//     going to the even one.
+func ShortestDecimal(m2 *big.Int, e2 int, lowerHalfGap bool) (digits *big.Int, exp int) {
+	if m2.Sign() <= 0 {
+		panic("verify: nonpositive mantissa")
+	}
+	// Represent the value and the bounds of the interval exactly as
+	// fractions over den, where v = 4*m2 * 2^(e2-2).
+	v := new(big.Int).Lsh(m2, 2)
+	hi := new(big.Int).Add(v, big.NewInt(2))
+	lo := new(big.Int).Sub(v, big.NewInt(2))
+	if lowerHalfGap {
+		lo.Add(lo, big.NewInt(1))
+	}
+	den := big.NewInt(1)
+	if e2 >= 2 {
+		v.Lsh(v, uint(e2-2))
+		hi.Lsh(hi, uint(e2-2))
+		lo.Lsh(lo, uint(e2-2))
+	} else {
+		den.Lsh(den, uint(2-e2))
+	}
+	inclusive := m2.Bit(0) == 0
+
+	// scaled returns x/den and 10^k as fractions with a common denominator.
+	scaled := func(x *big.Int, k int) (xs, q *big.Int) {
+		if k >= 0 {
+			return x, new(big.Int).Mul(pow10(k), den)
+		}
+		return new(big.Int).Mul(x, pow10(-k)), den
+	}
+	// multiples returns the range [nlo, nhi] of n such that n * 10^k
+	// lies in the interval. It is empty if nlo > nhi.
+	multiples := func(k int) (nlo, nhi *big.Int) {
+		los, q := scaled(lo, k)
+		his, _ := scaled(hi, k)
+		r := new(big.Int)
+		nlo, r = new(big.Int).QuoRem(los, q, r)
+		if r.Sign() != 0 || !inclusive {
+			nlo.Add(nlo, big.NewInt(1))
+		}
+		nhi, r = new(big.Int).QuoRem(his, q, r)
+		if r.Sign() == 0 && !inclusive {
+			nhi.Sub(nhi, big.NewInt(1))
+		}
+		return nlo, nhi
+	}
+
+	// The interval is wider than 2^(e2-1), so it contains a multiple of
+	// any power of 10 that is at most that. Move up from there to find
+	// the largest power of 10 that has a multiple in the interval.
+	k := int(math.Floor(float64(e2-1) * math.Log10(2)))
+	nlo, nhi := multiples(k)
+	for {
+		nlo1, nhi1 := multiples(k + 1)
+		if nlo1.Cmp(nhi1) > 0 {
+			break
+		}
+		k++
+		nlo, nhi = nlo1, nhi1
+	}
+
+	// Pick the multiple of 10^k closest to v.
+	vs, q := scaled(v, k)
+	below, r := new(big.Int).QuoRem(vs, q, new(big.Int))
+	above := new(big.Int).Add(below, big.NewInt(1))
+	switch {
+	case below.Cmp(nlo) < 0:
+		digits = above
+	case above.Cmp(nhi) > 0:
+		digits = below
+	default:
+		// Compare the distances r and q-r to below and above.
+		switch r.Lsh(r, 1).Cmp(q) {
+		case -1:
+			digits = below
+		case 1:
+			digits = above
+		default:
+			digits = below
+			if below.Bit(0) != 0 {
+				digits = above
+			}
+		}
+	}
+	return digits, k
+}
+
+// pow10s caches the powers of 10 that are needed for float64s.
+var pow10s = func() []*big.Int {
+	p := make([]*big.Int, 400)
+	p[0] = big.NewInt(1)
+	for i := 1; i < len(p); i++ {
+		p[i] = new(big.Int).Mul(p[i-1], big.NewInt(10))
+	}
+	return p
+}()
+
+// pow10 returns 10^k. The result must not be modified.
+func pow10(k int) *big.Int {
+	if k < len(pow10s) {
+		return pow10s[k]
+	}
+	return new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(k)), nil)
+}
+
+// Check64 checks that s is a correct shortest representation of f: that
+// it parses back to f (by strconv.ParseFloat), that no decimal with fewer
+// significant digits does, and that no decimal with as many significant
+// digits that does is closer to f. Ties may go either way. Leading and
+// trailing zeros are not significant. For infinities and NaNs, s only needs
+// to parse back to f. Check64 returns an error describing the first problem
+// it finds, or nil.
+func Check64(s string, f float64) error {
+	return check(s, f, 64)
+}
+
+// Check32 is like Check64 for float32s.
+func Check32(s string, f float32) error {
+	return check(s, float64(f), 32)
+}
+
+func check(s string, f float64, bitSize int) error {
+	g, err := strconv.ParseFloat(s, bitSize)
+	if err != nil {
+		return fmt.Errorf("verify: %q does not parse: %s", s, err)
+	}
+	if math.IsNaN(f) {
+		if !math.IsNaN(g) {
+			return fmt.Errorf("verify: %q parses to %v, not NaN", s, g)
+		}
+		return nil
+	}
+	if math.Float64bits(g) != math.Float64bits(f) {
+		return fmt.Errorf("verify: %q parses to %v, not %v", s, g, f)
+	}
+	if f == 0 || math.IsInf(f, 0) {
+		return nil
+	}
+
+	var (
+		digits *big.Int
+		exp    int
+	)
+	if bitSize == 32 {
+		digits, exp = shortest(uint64(math.Float32bits(float32(f))), 23, 8, 127)
+	} else {
+		digits, exp = shortest(math.Float64bits(f), 52, 11, 1023)
+	}
+	n, err := significantDigits(s)
+	if err != nil {
+		return err
+	}
+	switch want := len(digits.String()); {
+	case n > want:
+		return fmt.Errorf("verify: %q has %d significant digits; the shortest representation %se%d has %d",
+			s, n, digits, exp, want)
+	case n < want:
+		// This means that ShortestDecimal is wrong.
+		return fmt.Errorf("verify: %q has %d significant digits, fewer than %se%d", s, n, digits, exp)
+	}
+
+	// The representation has the right length; check that it is no
+	// further from f than the shortest one.
+	x, ok := new(big.Rat).SetString(strings.TrimPrefix(s, "-"))
+	if !ok {
+		return fmt.Errorf("verify: %q is not a decimal number", s)
+	}
+	want, _ := new(big.Rat).SetString(fmt.Sprintf("%se%d", digits, exp))
+	v := new(big.Rat).SetFloat64(math.Abs(f))
+	dx := new(big.Rat).Sub(x, v)
+	dw := new(big.Rat).Sub(want, v)
+	if dx.Abs(dx).Cmp(dw.Abs(dw)) > 0 {
+		return fmt.Errorf("verify: %q is not correctly rounded; %se%d is closer to %v", s, digits, exp, f)
+	}
+	return nil
+}
+
+// significantDigits returns the number of significant digits of the
+// decimal number s.
+func significantDigits(s string) (int, error) {
+	mant := s
+	if i := strings.IndexAny(mant, "eE"); i >= 0 {
+		mant = mant[:i]
+	}
+	mant = strings.TrimLeft(mant, "+-")
+	mant = strings.Replace(mant, ".", "", 1)
+	for _, c := range mant {
+		if c < '0' || c > '9' {
+			return 0, errors.New("verify: " + strconv.Quote(s) + " is not a decimal number")
+		}
+	}
+	return len(strings.Trim(mant, "0")), nil
+}
@@ -0,0 +1,111 @@
+// Copyright 2019 Caleb Spare
+//
+// The contents of this file may be used under the terms of the Apache License,
+// Version 2.0.
+//
+//    (See accompanying file LICENSE or copy at
+//     http://www.apache.org/licenses/LICENSE-2.0)
+//
+// Unless required by applicable law or agreed to in writing, this software
+// is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.
+
+package verify
+
+import (
+	"math"
+	"strings"
+	"testing"
+)
+
+func TestShortestDecimal64(t *testing.T) {
+	for _, tt := range []struct {
+		f      float64
+		digits uint64
+		exp    int
+	}{
+		{0, 0, 0},
+		{math.Inf(1), 0, 0},
+		{math.NaN(), 0, 0},
+		{1, 1, 0},
+		{-10, 1, 1},
+		{0.3, 3, -1},
+		{123.45, 12345, -2},
+		{1e23, 1, 23},
+		{99999999999999974834176, 9999999999999997, 7},
+		{5e-324, 5, -324},
+		{2.2250738585072014e-308, 22250738585072014, -324},
+		{math.MaxFloat64, 17976931348623157, 292},
+		// https://golang.org/issue/29491
+		{498484681984085570, 49848468198408557, 1},
+		{-5.8339553793802237e+23, 58339553793802237, 7},
+	} {
+		digits, exp := ShortestDecimal64(tt.f)
+		if digits != tt.digits || exp != tt.exp {
+			t.Errorf("ShortestDecimal64(%g): got %de%d; want %de%d", tt.f, digits, exp, tt.digits, tt.exp)
+		}
+	}
+}
+
+func TestShortestDecimal32(t *testing.T) {
+	for _, tt := range []struct {
+		f      float32
+		digits uint32
+		exp    int
+	}{
+		{0, 0, 0},
+		{1, 1, 0},
+		{-1, 1, 0},
+		{0.3, 3, -1},
+		{1e10, 1, 10},
+		{math.SmallestNonzeroFloat32, 1, -45},
+		{math.MaxFloat32, 34028235, 31},
+		{1.1754944e-38, 11754944, -45},
+	} {
+		digits, exp := ShortestDecimal32(tt.f)
+		if digits != tt.digits || exp != tt.exp {
+			t.Errorf("ShortestDecimal32(%g): got %de%d; want %de%d", tt.f, digits, exp, tt.digits, tt.exp)
+		}
+	}
+}
+
+func TestCheck64(t *testing.T) {
+	for _, tt := range []struct {
+		s   string
+		f   float64
+		err string // substring of the error, if any
+	}{
+		{"3e-01", 0.3, ""},
+		{"0.3", 0.3, ""},
+		{"-0.30", -0.3, ""},
+		{"300e-3", 0.3, ""},
+		{"5e-324", 5e-324, ""},
+		{"+Inf", math.Inf(1), ""},
+		{"NaN", math.NaN(), ""},
+		{"-0", math.Copysign(0, -1), ""},
+		{"0", math.Copysign(0, -1), "parses to"},
+		{"0.4", 0.3, "parses to"},
+		{"x", 0.3, "does not parse"},
+		{"0.29999999999999999", 0.3, "significant digits"},
+		{"4e-324", 5e-324, "not correctly rounded"},
+		{"4.9848468198408556e+17", 498484681984085570, "not correctly rounded"},
+		{"4.9848468198408557e+17", 498484681984085570, ""},
+	} {
+		err := Check64(tt.s, tt.f)
+		switch {
+		case err == nil && tt.err != "":
+			t.Errorf("Check64(%q, %g): got nil error; want %q", tt.s, tt.f, tt.err)
+		case err != nil && (tt.err == "" || !strings.Contains(err.Error(), tt.err)):
+			t.Errorf("Check64(%q, %g): got error %q; want %q", tt.s, tt.f, err, tt.err)
+		}
+	}
+}
+
+func TestCheck32(t *testing.T) {
+	if err := Check32("1e-45", math.SmallestNonzeroFloat32); err != nil {
+		t.Error(err)
+	}
+	if err := Check32("1.4e-45", math.SmallestNonzeroFloat32); err == nil {
+		t.Error("Check32 accepted a representation that is not shortest")
+	}
+}